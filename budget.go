@@ -0,0 +1,303 @@
+package godeeplapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+// ErrBudgetExceeded matches every *BudgetExceededError with errors.Is.
+var ErrBudgetExceeded = errors.New("budget exceeded")
+
+// Budget scopes reported by BudgetExceededError
+const (
+	BudgetPerJob = "per-job"
+	BudgetPerDay = "per-day"
+	BudgetQuota  = "quota"
+)
+
+// BudgetExceededError is returned when a request would exceed a budget.
+type BudgetExceededError struct {
+	// Scope is BudgetPerJob, BudgetPerDay or BudgetQuota.
+	Scope string
+	// Requested is the estimated number of characters of the request.
+	Requested int64
+	// Available is the number of characters left in the scope.
+	Available int64
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("%s budget exceeded: request needs %d characters, %d available", e.Scope, e.Requested, e.Available)
+}
+
+func (e *BudgetExceededError) Is(target error) bool {
+	return target == ErrBudgetExceeded
+}
+
+var markupTag = regexp.MustCompile(`<[^<>]*>`)
+
+// EstimateCharacters returns the number of characters DeepL bills for the
+// request: all characters of Text, counted as Unicode code points. Context is
+// free. With TagHandling set, markup tags are not counted.
+func EstimateCharacters(req models.TranslationRequest) int64 {
+	var n int64
+	for _, text := range req.Text {
+		if req.TagHandling != "" {
+			text = markupTag.ReplaceAllString(text, "")
+		}
+		n += int64(utf8.RuneCountInString(text))
+	}
+	return n
+}
+
+// BudgetLimits configures a BudgetGuard. Zero values disable a check.
+type BudgetLimits struct {
+	// PerJob is the maximum number of characters of a job: all requests
+	// made with a context from WithBudgetJob, or else a single request.
+	PerJob int64
+	// PerDay is the maximum number of characters per local calendar day,
+	// counted by the guard.
+	PerDay int64
+	// CheckQuota rejects requests that would leave less than QuotaReserve
+	// characters of the account quota reported by GetUsageAndLimits.
+	CheckQuota   bool
+	QuotaReserve int64
+	// Pause makes requests that exceed PerDay wait for the next day instead
+	// of failing. The other limits always fail.
+	Pause bool
+	// UsageTTL is how long the account usage is cached. Zero means one
+	// minute.
+	UsageTTL time.Duration
+}
+
+// BudgetGuard rejects requests that would exceed the configured budgets. It
+// is safe for concurrent use.
+type BudgetGuard struct {
+	client *Client
+	limits BudgetLimits
+
+	mu    sync.Mutex
+	day   string
+	spent int64
+
+	usage     *models.UsageAndLimitResponse
+	usageAt   time.Time
+	sinceSync int64
+	syncing   *usageFetch
+}
+
+// usageFetch is a running usage request shared by concurrent callers.
+type usageFetch struct {
+	done chan struct{}
+	err  error
+}
+
+// NewBudgetGuard creates a guard that fetches the account usage with client.
+func NewBudgetGuard(client *Client, limits BudgetLimits) *BudgetGuard {
+	if limits.UsageTTL <= 0 {
+		limits.UsageTTL = time.Minute
+	}
+	return &BudgetGuard{client: client, limits: limits}
+}
+
+// Spent returns the characters reserved today.
+func (g *BudgetGuard) Spent() int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.rollover(time.Now())
+	return g.spent
+}
+
+// BudgetJob counts the characters of the requests of a job, such as the
+// translation of a file that is split into many requests. It is safe for
+// concurrent use.
+type BudgetJob struct {
+	mu    sync.Mutex
+	spent int64
+}
+
+type budgetJobKey struct{}
+
+// WithBudgetJob returns a context that makes every request made with it
+// count towards the same BudgetLimits.PerJob limit:
+//
+//	ctx, job := godeeplapi.WithBudgetJob(ctx)
+//	_, err := po.Translate(ctx, client, file, req)
+func WithBudgetJob(ctx context.Context) (context.Context, *BudgetJob) {
+	job := &BudgetJob{}
+	return context.WithValue(ctx, budgetJobKey{}, job), job
+}
+
+// Spent returns the characters reserved for the job.
+func (j *BudgetJob) Spent() int64 {
+	if j == nil {
+		return 0
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.spent
+}
+
+// add changes the characters reserved for the job. A nil job is a single
+// request, which is not tracked.
+func (j *BudgetJob) add(chars int64) {
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.spent = max(j.spent+chars, 0)
+}
+
+func budgetJob(ctx context.Context) *BudgetJob {
+	job, _ := ctx.Value(budgetJobKey{}).(*BudgetJob)
+	return job
+}
+
+// Check reports whether a request of chars characters fits the budgets,
+// without reserving them.
+func (g *BudgetGuard) Check(ctx context.Context, chars int64) error {
+	if err := g.syncUsage(ctx); err != nil {
+		return err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.check(budgetJob(ctx), chars)
+}
+
+// Reserve checks the budgets and counts chars as spent. With Pause set it
+// waits for the next day when only the daily budget is exhausted.
+func (g *BudgetGuard) Reserve(ctx context.Context, chars int64) error {
+	for {
+		if err := g.syncUsage(ctx); err != nil {
+			return err
+		}
+		job := budgetJob(ctx)
+		g.mu.Lock()
+		err := g.check(job, chars)
+		if err == nil {
+			g.spent += chars
+			g.sinceSync += chars
+			job.add(chars)
+			g.mu.Unlock()
+			return nil
+		}
+		g.mu.Unlock()
+
+		var budgetErr *BudgetExceededError
+		if !g.limits.Pause || !errors.As(err, &budgetErr) || budgetErr.Scope != BudgetPerDay || chars > g.limits.PerDay {
+			return err
+		}
+
+		now := time.Now()
+		year, month, day := now.Date()
+		wait := time.Date(year, month, day+1, 0, 0, 0, 0, now.Location()).Sub(now)
+		g.client.logger.Info("Daily budget exhausted, pausing for %v", wait.Round(time.Second))
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Release gives back chars reserved with ctx for a request that failed, so
+// that failed requests and retries do not use up the budgets.
+func (g *BudgetGuard) Release(ctx context.Context, chars int64) {
+	budgetJob(ctx).add(-chars)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.rollover(time.Now())
+	g.spent = max(g.spent-chars, 0)
+	g.sinceSync = max(g.sinceSync-chars, 0)
+}
+
+// syncUsage fetches the account usage when CheckQuota is set and the cached
+// usage is older than UsageTTL. Concurrent callers share one request, and
+// g.mu is not held during it, so other requests are not blocked by it.
+// Characters reserved while the request runs are kept in sinceSync, since
+// the usage may not include them.
+func (g *BudgetGuard) syncUsage(ctx context.Context) error {
+	if !g.limits.CheckQuota {
+		return nil
+	}
+	for {
+		g.mu.Lock()
+		if g.usage != nil && time.Since(g.usageAt) <= g.limits.UsageTTL {
+			g.mu.Unlock()
+			return nil
+		}
+		running := g.syncing
+		if running == nil {
+			break
+		}
+		g.mu.Unlock()
+
+		select {
+		case <-running.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		// Fetch again when only the context of the other caller ended
+		if !errors.Is(running.err, context.Canceled) && !errors.Is(running.err, context.DeadlineExceeded) {
+			return running.err
+		}
+	}
+	running := &usageFetch{done: make(chan struct{})}
+	g.syncing = running
+	synced := g.sinceSync
+	g.mu.Unlock()
+
+	usage, err := g.client.GetUsageAndLimits(ctx)
+	if err != nil {
+		err = fmt.Errorf("error checking quota: %w", err)
+	}
+
+	g.mu.Lock()
+	if err == nil {
+		g.usage, g.usageAt = usage, time.Now()
+		g.sinceSync = max(g.sinceSync-synced, 0)
+	}
+	g.syncing = nil
+	g.mu.Unlock()
+	running.err = err
+	close(running.done)
+	return err
+}
+
+// check reports whether chars fit the budgets. job is nil for a request
+// outside of a job. g.mu must be held and the usage synced with syncUsage.
+func (g *BudgetGuard) check(job *BudgetJob, chars int64) error {
+	if g.limits.PerJob > 0 {
+		if jobSpent := job.Spent(); jobSpent+chars > g.limits.PerJob {
+			return &BudgetExceededError{Scope: BudgetPerJob, Requested: chars, Available: max(g.limits.PerJob-jobSpent, 0)}
+		}
+	}
+
+	g.rollover(time.Now())
+	if g.limits.PerDay > 0 && g.spent+chars > g.limits.PerDay {
+		return &BudgetExceededError{Scope: BudgetPerDay, Requested: chars, Available: g.limits.PerDay - g.spent}
+	}
+
+	if g.limits.CheckQuota && g.usage != nil {
+		available := g.usage.CharLimit - g.usage.CharCount - g.sinceSync - g.limits.QuotaReserve
+		if chars > available {
+			return &BudgetExceededError{Scope: BudgetQuota, Requested: chars, Available: max(available, 0)}
+		}
+	}
+	return nil
+}
+
+// rollover resets the daily count when the day has changed.
+func (g *BudgetGuard) rollover(now time.Time) {
+	if day := now.Format(time.DateOnly); day != g.day {
+		g.day = day
+		g.spent = 0
+	}
+}
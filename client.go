@@ -1,9 +1,13 @@
 package godeeplapi
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/AdolfZahid1/godeeplapi/models"
 )
 
 type Client struct {
@@ -11,6 +15,16 @@ type Client struct {
 	authKey    string
 	httpClient *http.Client
 	logger     Logger
+	memory     TranslationMemory
+	fuzzy      *FuzzyOptions
+	languages  *LanguageRegistry
+	budget     *BudgetGuard
+	offline    Translator
+
+	maxRetries     int
+	initialBackoff time.Duration
+	pollStrategy   PollStrategy
+	output         OutputOptions
 }
 
 type ClientOption func(*Client)
@@ -36,6 +50,102 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
+// WithRetry retries requests that fail with status 429 or 5xx, a truncated
+// response or a network timeout up to maxRetries times, waiting
+// initialBackoff before the first retry and doubling it after each one. A
+// backoff <= 0 means one second. Document uploads and downloads are not
+// retried
+func WithRetry(maxRetries int, initialBackoff time.Duration) ClientOption {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.initialBackoff = initialBackoff
+	}
+}
+
+// WithPollStrategy sets how often the status of documents is checked while
+// waiting for their translation, see PollStrategy
+func WithPollStrategy(strategy PollStrategy) ClientOption {
+	return func(c *Client) {
+		c.pollStrategy = strategy
+	}
+}
+
+// WithOutputOptions sets how DownloadDocument, ResumeDocument and
+// TranslateFile name their files and handle existing ones, see OutputOptions
+func WithOutputOptions(opts OutputOptions) ClientOption {
+	return func(c *Client) {
+		c.output = opts
+	}
+}
+
+// WithBaseURL sets the API base URL including the version, e.g. a proxy or a
+// deepltest server: "http://127.0.0.1:8080/v2"
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithTranslationMemory makes Translate serve exact matches from memory and
+// send only the remaining texts to the API
+func WithTranslationMemory(memory TranslationMemory) ClientOption {
+	return func(c *Client) {
+		c.memory = memory
+	}
+}
+
+// WithFuzzyMatching enables fuzzy lookups for texts without an exact match
+// in the translation memory. The memory must implement FuzzyTranslationMemory
+func WithFuzzyMatching(opts FuzzyOptions) ClientOption {
+	return func(c *Client) {
+		c.fuzzy = &opts
+	}
+}
+
+// WithLanguageRegistry validates request languages against the live language
+// lists, cached for ttl. A ttl <= 0 means DefaultLanguageTTL
+func WithLanguageRegistry(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.languages = NewLanguageRegistry(c, ttl)
+	}
+}
+
+// Languages returns the registry set up by WithLanguageRegistry, or nil
+func (c *Client) Languages() *LanguageRegistry {
+	return c.languages
+}
+
+// languageSupport returns the live language lists for request validation,
+// or nil to use the built-in checks
+func (c *Client) languageSupport(ctx context.Context) models.LanguageSupport {
+	if c.languages == nil {
+		return nil
+	}
+	return c.languages.languageSupport(ctx)
+}
+
+// WithBudget makes Translate reject requests that would exceed the limits,
+// see BudgetGuard. Texts served from the translation memory are not counted
+func WithBudget(limits BudgetLimits) ClientOption {
+	return func(c *Client) {
+		c.budget = NewBudgetGuard(c, limits)
+	}
+}
+
+// Budget returns the guard set up by WithBudget, or nil
+func (c *Client) Budget() *BudgetGuard {
+	return c.budget
+}
+
+// WithOfflineTranslator makes Translate use t instead of calling the API, e.g.
+// pseudo.New() to test a localization pipeline without a DeepL account. No
+// API key is needed for text translation then
+func WithOfflineTranslator(t Translator) ClientOption {
+	return func(c *Client) {
+		c.offline = t
+	}
+}
+
 // NewClient creates a new DeepL API client for v2 API
 func NewClient(apiKey string, isPro bool, opts ...ClientOption) *Client {
 	return newClientWithVersion(apiKey, isPro, "v2", opts...)
@@ -0,0 +1,391 @@
+package deepltest
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Mode selects whether a Cassette records or replays.
+type Mode int
+
+const (
+	// ModeReplay answers requests from the cassette only. Requests without
+	// a recorded interaction fail with ErrNoInteraction.
+	ModeReplay Mode = iota
+	// ModeRecord sends every request and records it, replacing the cassette.
+	ModeRecord
+	// ModeRecordMissing replays recorded interactions and sends and records
+	// the other requests.
+	ModeRecordMissing
+)
+
+// ErrNoInteraction is returned in ModeReplay for requests that were not
+// recorded.
+var ErrNoInteraction = errors.New("deepltest: no recorded interaction")
+
+// Redacted replaces secrets in cassettes.
+const Redacted = "REDACTED"
+
+// redactedFields are the body fields replaced with Redacted.
+var redactedFields = map[string]bool{
+	"auth_key":     true,
+	"document_key": true,
+}
+
+// Interaction is a recorded request and its response, one line of a
+// cassette file.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the part of a request that is matched on replay.
+type RecordedRequest struct {
+	Method string `json:"method"`
+	// Path includes the sorted query, e.g. "/v2/languages?type=target".
+	Path string `json:"path"`
+	// Body is the normalized body: JSON with sorted keys, sorted form
+	// fields, or multipart fields with a hash of the file contents.
+	Body string `json:"body,omitempty"`
+}
+
+// RecordedResponse is a recorded response.
+type RecordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+	// Base64 is set when Body is base64 encoded because it is not UTF-8.
+	Base64 bool `json:"base64,omitempty"`
+}
+
+// Cassette is an http.RoundTripper that records DeepL interactions to a JSON
+// Lines file and replays them, so that integration tests can run in CI
+// without an API key:
+//
+//	cassette, err := deepltest.NewCassette("testdata/translate.jsonl", deepltest.ModeReplay, nil)
+//	client := godeeplapi.NewClient(key, false, godeeplapi.WithHTTPClient(&http.Client{Transport: cassette}))
+//
+// The Authorization header is never recorded and document keys are redacted.
+// Requests are matched by method, path and normalized body; each recorded
+// interaction is replayed once, in order, so that repeated requests such as
+// document status checks get their recorded sequence of responses. It is safe
+// for concurrent use.
+type Cassette struct {
+	path string
+	mode Mode
+	next http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewCassette opens the cassette at path. next sends the requests to record,
+// http.DefaultTransport if nil. In ModeRecord the file is truncated; in the
+// other modes it is loaded and must exist in ModeReplay.
+func NewCassette(path string, mode Mode, next http.RoundTripper) (*Cassette, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c := &Cassette{path: path, mode: mode, next: next}
+
+	if mode == ModeRecord {
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			return nil, fmt.Errorf("error creating cassette: %w", err)
+		}
+		return c, nil
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) && mode == ModeRecordMissing {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening cassette: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 64<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var in Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &in); err != nil {
+			return nil, fmt.Errorf("error decoding cassette line %d: %w", line, err)
+		}
+		c.interactions = append(c.interactions, in)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading cassette: %w", err)
+	}
+	c.used = make([]bool, len(c.interactions))
+	return c, nil
+}
+
+// Unused returns the recorded interactions that were not replayed.
+func (c *Cassette) Unused() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []Interaction
+	for i, in := range c.interactions {
+		if !c.used[i] {
+			out = append(out, in)
+		}
+	}
+	return out
+}
+
+// RoundTrip implements http.RoundTripper.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading request body: %w", err)
+		}
+	}
+	recorded := RecordedRequest{
+		Method: req.Method,
+		Path:   requestPath(req.URL),
+		Body:   normalizeBody(req.Header.Get("Content-Type"), body),
+	}
+
+	if c.mode != ModeRecord {
+		if in, ok := c.replay(recorded); ok {
+			return in.Response.response(req)
+		}
+		if c.mode == ModeReplay {
+			return nil, fmt.Errorf("%w for %s %s", ErrNoInteraction, recorded.Method, recorded.Path)
+		}
+	}
+
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+	resp, err := c.next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	if err := c.record(Interaction{Request: recorded, Response: recordResponse(resp, respBody)}); err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	resp.Request = req
+	return resp, nil
+}
+
+// replay returns the first unused interaction matching req.
+func (c *Cassette) replay(req RecordedRequest) (Interaction, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, in := range c.interactions {
+		if !c.used[i] && in.Request == req {
+			c.used[i] = true
+			return in, true
+		}
+	}
+	return Interaction{}, false
+}
+
+// record appends an interaction to the cassette file.
+func (c *Cassette) record(in Interaction) error {
+	line, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("error encoding interaction: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f, err := os.OpenFile(c.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening cassette: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("error writing cassette: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error writing cassette: %w", err)
+	}
+	c.interactions = append(c.interactions, in)
+	c.used = append(c.used, true)
+	return nil
+}
+
+func recordResponse(resp *http.Response, body []byte) RecordedResponse {
+	out := RecordedResponse{Status: resp.StatusCode, Header: resp.Header.Clone()}
+	out.Header.Del("Set-Cookie")
+	out.Header.Del("Date")
+	out.Header.Del("Content-Length")
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		body = redactJSON(body)
+	}
+	if utf8.Valid(body) {
+		out.Body = string(body)
+	} else {
+		out.Body = base64.StdEncoding.EncodeToString(body)
+		out.Base64 = true
+	}
+	return out
+}
+
+// response builds the http.Response for req.
+func (r RecordedResponse) response(req *http.Request) (*http.Response, error) {
+	body := []byte(r.Body)
+	if r.Base64 {
+		var err error
+		if body, err = base64.StdEncoding.DecodeString(r.Body); err != nil {
+			return nil, fmt.Errorf("error decoding recorded body: %w", err)
+		}
+	}
+	header := r.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	if req.Method == http.MethodHead {
+		body = nil
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// requestPath returns the path and the sorted query of u.
+func requestPath(u *url.URL) string {
+	query := u.Query()
+	for field := range redactedFields {
+		if query.Has(field) {
+			query.Set(field, Redacted)
+		}
+	}
+	if len(query) == 0 {
+		return u.Path
+	}
+	return u.Path + "?" + query.Encode()
+}
+
+// normalizeBody returns a redacted body that does not depend on the field
+// order or the multipart boundary.
+func normalizeBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/json":
+		return string(redactJSON(body))
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			break
+		}
+		for field := range redactedFields {
+			if values.Has(field) {
+				values.Set(field, Redacted)
+			}
+		}
+		return values.Encode()
+	case strings.HasPrefix(mediaType, "multipart/"):
+		if fields, err := multipartFields(body, params["boundary"]); err == nil {
+			return fields
+		}
+	}
+	return string(body)
+}
+
+// multipartFields lists the fields of a multipart body as sorted lines. Files
+// are listed with their name and the SHA-256 of their content.
+func multipartFields(body []byte, boundary string) (string, error) {
+	r := multipart.NewReader(bytes.NewReader(body), boundary)
+	var lines []string
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return "", err
+		}
+		value := string(data)
+		if part.FileName() != "" {
+			sum := sha256.Sum256(data)
+			value = "file " + part.FileName() + " sha256:" + hex.EncodeToString(sum[:])
+		} else if redactedFields[part.FormName()] {
+			value = Redacted
+		}
+		lines = append(lines, part.FormName()+"="+value)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n"), nil
+}
+
+// redactJSON replaces the redacted fields and re-encodes the document with
+// sorted keys. Invalid JSON is returned unchanged.
+func redactJSON(data []byte) []byte {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return data
+	}
+	out, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if redactedFields[key] {
+				v[key] = Redacted
+			} else {
+				v[key] = redactValue(value)
+			}
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = redactValue(value)
+		}
+	}
+	return v
+}
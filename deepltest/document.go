@@ -0,0 +1,182 @@
+package deepltest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+// maxDocumentSize is the upload size limit of /document.
+const maxDocumentSize = 30 << 20
+
+type document struct {
+	id         string
+	key        string
+	sourceLang models.Language
+	targetLang models.Language
+	filename   string
+	content    string
+	entries    map[string]string
+	chars      int64
+	errMsg     string
+	polls      int
+	done       bool
+}
+
+func (s *Server) handleDocumentUpload(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxDocumentSize)
+	if err := r.ParseMultipartForm(maxDocumentSize); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid multipart request: "+err.Error())
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Parameter 'file' not specified.")
+		return
+	}
+	defer file.Close()
+
+	sourceLang, targetLang := r.FormValue("source_lang"), r.FormValue("target_lang")
+	glossaryID := r.FormValue("glossary_id")
+	if msg := s.checkTarget(sourceLang, targetLang, r.FormValue("formality"), glossaryID); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+	fileName := r.FormValue("filename")
+	if fileName == "" {
+		fileName = header.Filename
+	}
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Error reading file: "+err.Error())
+		return
+	}
+	if !utf8.Valid(content) {
+		writeError(w, http.StatusBadRequest, "File type not supported.")
+		return
+	}
+
+	d := &document{
+		sourceLang: models.Language(sourceLang),
+		targetLang: models.Language(targetLang),
+		filename:   filepath.Base(fileName),
+		content:    string(content),
+		chars:      int64(utf8.RuneCount(content)),
+	}
+	if outputFormat := r.FormValue("output_format"); outputFormat != "" {
+		d.filename = strings.TrimSuffix(d.filename, filepath.Ext(d.filename)) + "." + outputFormat
+	}
+	if glossaryID != "" {
+		var status int
+		var msg string
+		if d.entries, status, msg = s.glossaryEntries(glossaryID, d.sourceLang, d.targetLang); msg != "" {
+			writeError(w, status, msg)
+			return
+		}
+	}
+	if !s.bill(d.chars) {
+		writeError(w, godeeplapi.ErrQuotaExceeded.StatusCode, "Quota exceeded")
+		return
+	}
+
+	s.mu.Lock()
+	d.id = s.newID()
+	d.key = newKey()
+	d.errMsg = s.docError
+	s.documents[d.id] = d
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, models.DocumentResponse{DocumentId: d.id, DocumentKey: d.key})
+}
+
+func (s *Server) handleDocumentStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, status, msg := s.document(r)
+	if msg != "" {
+		writeError(w, status, msg)
+		return
+	}
+
+	resp := models.DocumentStatusResponse{DocumentId: d.id}
+	switch poll := d.polls; {
+	case poll < s.queuedPolls:
+		resp.DocumentStatus = models.DocumentStatusQueued
+	case poll < s.queuedPolls+s.translatingPolls:
+		resp.DocumentStatus = models.DocumentStatusTranslating
+	case d.errMsg != "":
+		resp.DocumentStatus = models.DocumentStatusError
+		resp.ErrorMessage = d.errMsg
+	default:
+		resp.DocumentStatus = models.DocumentStatusDone
+		resp.BilledChars = int(d.chars)
+		d.done = true
+	}
+	d.polls++
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleDocumentResult serves the translated document. The document is
+// deleted after a successful POST, like in the API; HEAD only reports the
+// file name.
+func (s *Server) handleDocumentResult(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	d, status, msg := s.document(r)
+	if msg == "" && !d.done {
+		status, msg = http.StatusServiceUnavailable, "Document not ready."
+	}
+	if msg != "" {
+		s.mu.Unlock()
+		writeError(w, status, msg)
+		return
+	}
+	if r.Method == http.MethodPost {
+		delete(s.documents, d.id)
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": d.filename}))
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodPost {
+		io.WriteString(w, s.translateText(d.content, d.sourceLang, d.targetLang, d.entries))
+	}
+}
+
+// document returns the document of the request after checking its key, or
+// an error status and message. s.mu must be held.
+func (s *Server) document(r *http.Request) (*document, int, string) {
+	d, ok := s.documents[r.PathValue("id")]
+	if !ok {
+		return nil, http.StatusNotFound, "Document not found."
+	}
+
+	var body struct {
+		DocumentKey string `json:"document_key"`
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		json.NewDecoder(r.Body).Decode(&body)
+	} else {
+		body.DocumentKey = r.FormValue("document_key")
+	}
+	if body.DocumentKey != d.key {
+		return nil, http.StatusForbidden, "Document key does not match."
+	}
+	return d, 0, ""
+}
+
+func newKey() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return strings.ToUpper(hex.EncodeToString(b))
+}
@@ -0,0 +1,237 @@
+package deepltest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrTimeout is a network timeout, for TransportFault.Err. The client
+// treats it as retryable.
+var ErrTimeout error = timeoutError{}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "deepltest: i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// TransportFault is a fault injected by a FaultTransport. A matching request
+// is delayed by Delay and then fails with Err, gets a response with Status,
+// or is sent and gets its response body truncated, whichever is set first.
+// A fault with only Delay set makes slow responses.
+type TransportFault struct {
+	// Method and Path select the requests, e.g. "POST" and "/document/".
+	// Path is matched as a prefix of the path without the version. Empty
+	// values match every request.
+	Method string
+	Path   string
+
+	// Probability is the chance that a matching request gets the fault.
+	// Zero means every matching request.
+	Probability float64
+	// After skips the first After matching requests, e.g. to fail the
+	// third document status check.
+	After int
+	// Times is how often the fault occurs. Zero means unlimited.
+	Times int
+
+	// Delay is waited before the request is sent or the fault is returned.
+	Delay time.Duration
+	// Err is returned instead of a response, e.g. ErrTimeout.
+	Err error
+	// Status is the status of the response returned instead of sending the
+	// request, with Message in the body and a Retry-After header if
+	// RetryAfter is set.
+	Status     int
+	Message    string
+	RetryAfter time.Duration
+	// Truncate cuts the response body after TruncateAt bytes; reading more
+	// fails with io.ErrUnexpectedEOF.
+	Truncate   bool
+	TruncateAt int
+}
+
+// FaultTransport is an http.RoundTripper that injects faults into requests
+// to the DeepL API, to test how code behaves on rate limits, exhausted
+// quotas, outages, slow and truncated responses:
+//
+//	transport := deepltest.NewFaultTransport(nil, deepltest.TransportFault{
+//		Path: "/translate", Status: http.StatusServiceUnavailable, Probability: 0.2,
+//	})
+//	client := godeeplapi.NewClient(key, false, godeeplapi.WithHTTPClient(&http.Client{Transport: transport}))
+//
+// It is safe for concurrent use.
+type FaultTransport struct {
+	next http.RoundTripper
+
+	mu       sync.Mutex
+	faults   []*faultState
+	rand     *rand.Rand
+	injected int
+}
+
+type faultState struct {
+	TransportFault
+	matched  int
+	injected int
+}
+
+// NewFaultTransport creates a FaultTransport that sends requests with next,
+// http.DefaultTransport if nil. Faults are checked in order and at most one
+// is applied per request. Random faults are reproducible, see Seed.
+func NewFaultTransport(next http.RoundTripper, faults ...TransportFault) *FaultTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	t := &FaultTransport{next: next, rand: rand.New(rand.NewPCG(1, 1))}
+	for _, f := range faults {
+		t.Add(f)
+	}
+	return t
+}
+
+// Add adds a fault after the existing ones.
+func (t *FaultTransport) Add(f TransportFault) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.faults = append(t.faults, &faultState{TransportFault: f})
+}
+
+// Seed reseeds the random source used for Probability.
+func (t *FaultTransport) Seed(seed uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rand = rand.New(rand.NewPCG(seed, seed))
+}
+
+// Injected returns the number of injected faults.
+func (t *FaultTransport) Injected() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.injected
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *FaultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f, ok := t.fault(req)
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	if f.Delay > 0 {
+		select {
+		case <-time.After(f.Delay):
+		case <-req.Context().Done():
+			closeBody(req)
+			return nil, req.Context().Err()
+		}
+	}
+
+	switch {
+	case f.Err != nil:
+		closeBody(req)
+		return nil, f.Err
+	case f.Status != 0:
+		closeBody(req)
+		return faultResponse(req, f.TransportFault), nil
+	case f.Truncate:
+		resp, err := t.next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = &truncatedBody{body: resp.Body, remaining: f.TruncateAt}
+		return resp, nil
+	}
+	return t.next.RoundTrip(req)
+}
+
+// fault returns a copy of the fault to apply to req, if any.
+func (t *FaultTransport) fault(req *http.Request) (faultState, bool) {
+	path := apiPath(req.URL.Path)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, f := range t.faults {
+		if (f.Method != "" && f.Method != req.Method) || !strings.HasPrefix(path, f.Path) {
+			continue
+		}
+		if f.Times > 0 && f.injected >= f.Times {
+			continue
+		}
+		if f.matched++; f.matched <= f.After {
+			continue
+		}
+		if f.Probability > 0 && t.rand.Float64() >= f.Probability {
+			continue
+		}
+		f.injected++
+		t.injected++
+		return *f, true
+	}
+	return faultState{}, false
+}
+
+func faultResponse(req *http.Request, f TransportFault) *http.Response {
+	message := f.Message
+	if message == "" {
+		message = http.StatusText(f.Status)
+	}
+	body, _ := json.Marshal(map[string]string{"message": message})
+
+	header := http.Header{"Content-Type": {"application/json"}}
+	if f.RetryAfter > 0 {
+		header.Set("Retry-After", strconv.Itoa(int(f.RetryAfter.Round(time.Second)/time.Second)))
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Status, http.StatusText(f.Status)),
+		StatusCode:    f.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// closeBody closes the request body, as a RoundTripper must also do when it
+// does not send the request.
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+// truncatedBody returns the first remaining bytes of body and then fails.
+type truncatedBody struct {
+	body      io.ReadCloser
+	remaining int
+}
+
+func (b *truncatedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	if len(p) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.body.Read(p)
+	b.remaining -= n
+	if err == io.EOF {
+		return n, io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func (b *truncatedBody) Close() error {
+	return b.body.Close()
+}
@@ -0,0 +1,389 @@
+package deepltest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+type glossary struct {
+	id           string
+	name         string
+	created      time.Time
+	dictionaries []*dictionary
+}
+
+type dictionary struct {
+	sourceLang models.Language
+	targetLang models.Language
+	entries    [][2]string
+}
+
+// glossaryRequest is the body of a glossary creation or edit as sent on the
+// wire.
+type glossaryRequest struct {
+	Name         string              `json:"name"`
+	Dictionaries []dictionaryRequest `json:"dictionaries"`
+}
+
+// dictionaryRequest is a dictionary of a glossary request.
+type dictionaryRequest struct {
+	SourceLang    string `json:"source_lang"`
+	TargetLang    string `json:"target_lang"`
+	Entries       string `json:"entries"`
+	EntriesFormat string `json:"entries_format"`
+}
+
+type glossaryJSON struct {
+	GlossaryID   string           `json:"glossary_id"`
+	Name         string           `json:"name"`
+	Dictionaries []dictionaryJSON `json:"dictionaries"`
+	CreationTime string           `json:"creation_time"`
+}
+
+type dictionaryJSON struct {
+	SourceLang    string `json:"source_lang"`
+	TargetLang    string `json:"target_lang"`
+	EntryCount    int    `json:"entry_count,omitempty"`
+	Entries       string `json:"entries,omitempty"`
+	EntriesFormat string `json:"entries_format,omitempty"`
+}
+
+func (g *glossary) json() glossaryJSON {
+	out := glossaryJSON{
+		GlossaryID:   g.id,
+		Name:         g.name,
+		Dictionaries: make([]dictionaryJSON, len(g.dictionaries)),
+		CreationTime: g.created.Format(time.RFC3339Nano),
+	}
+	for i, d := range g.dictionaries {
+		out.Dictionaries[i] = d.json(false)
+	}
+	return out
+}
+
+func (d *dictionary) json(withEntries bool) dictionaryJSON {
+	out := dictionaryJSON{
+		SourceLang: strings.ToLower(d.sourceLang.String()),
+		TargetLang: strings.ToLower(d.targetLang.String()),
+		EntryCount: len(d.entries),
+	}
+	if withEntries {
+		lines := make([]string, len(d.entries))
+		for i, e := range d.entries {
+			lines[i] = e[0] + "\t" + e[1]
+		}
+		out.Entries = strings.Join(lines, "\n")
+		out.EntriesFormat = "tsv"
+	}
+	return out
+}
+
+// dictionary returns the dictionary for a language pair, or nil.
+func (g *glossary) dictionary(sourceLang, targetLang models.Language) *dictionary {
+	for _, d := range g.dictionaries {
+		if d.sourceLang == sourceLang.SourceCode() && d.targetLang == targetLang.SourceCode() {
+			return d
+		}
+	}
+	return nil
+}
+
+// put replaces the dictionary for the language pair of d or adds it.
+func (g *glossary) put(d *dictionary) {
+	for i, existing := range g.dictionaries {
+		if existing.sourceLang == d.sourceLang && existing.targetLang == d.targetLang {
+			g.dictionaries[i] = d
+			return
+		}
+	}
+	g.dictionaries = append(g.dictionaries, d)
+}
+
+// newDictionary checks a dictionary of a request and parses its entries. It
+// returns an error message if the dictionary is invalid.
+func (s *Server) newDictionary(d dictionaryRequest) (*dictionary, string) {
+	if msg := s.checkDictionary(d); msg != "" {
+		return nil, msg
+	}
+	out := &dictionary{sourceLang: models.Language(baseLanguage(d.SourceLang)), targetLang: models.Language(baseLanguage(d.TargetLang))}
+	entries, err := parseEntries(d.Entries, d.EntriesFormat)
+	if err != nil {
+		return nil, "Invalid glossary entries: " + err.Error()
+	}
+	out.entries = entries
+	return out, ""
+}
+
+// parseEntries parses TSV or CSV glossary entries.
+func parseEntries(entries, format string) ([][2]string, error) {
+	var records [][]string
+	if format == "csv" {
+		r := csv.NewReader(strings.NewReader(entries))
+		r.FieldsPerRecord = -1
+		var err error
+		if records, err = r.ReadAll(); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, line := range strings.Split(entries, "\n") {
+			if line = strings.TrimRight(line, "\r"); line != "" {
+				records = append(records, strings.Split(line, "\t"))
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	out := make([][2]string, 0, len(records))
+	for i, record := range records {
+		if len(record) != 2 || strings.TrimSpace(record[0]) == "" || strings.TrimSpace(record[1]) == "" {
+			return nil, fmt.Errorf("entry %d must have a source and a target term", i+1)
+		}
+		if seen[record[0]] {
+			return nil, fmt.Errorf("duplicate source term %q", record[0])
+		}
+		seen[record[0]] = true
+		out = append(out, [2]string{record[0], record[1]})
+	}
+	return out, nil
+}
+
+// glossaryEntries returns the entries of a glossary for a language pair as a
+// map, or an error status and message.
+func (s *Server) glossaryEntries(id string, sourceLang, targetLang models.Language) (map[string]string, int, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.glossaries[id]
+	if !ok {
+		return nil, http.StatusNotFound, "Glossary not found."
+	}
+	d := g.dictionary(sourceLang, targetLang)
+	if d == nil {
+		return nil, http.StatusBadRequest, "Glossary does not contain a dictionary for the language pair."
+	}
+	entries := make(map[string]string, len(d.entries))
+	for _, e := range d.entries {
+		entries[e[0]] = e[1]
+	}
+	return entries, 0, ""
+}
+
+func (s *Server) handleGlossaryPairs(w http.ResponseWriter, r *http.Request) {
+	resp := struct {
+		SupportedLanguages []dictionaryJSON `json:"supported_languages"`
+	}{}
+	for _, source := range s.sourceLanguages {
+		for _, target := range s.sourceLanguages {
+			if source.Language != target.Language {
+				resp.SupportedLanguages = append(resp.SupportedLanguages, dictionaryJSON{
+					SourceLang: strings.ToLower(source.Language),
+					TargetLang: strings.ToLower(target.Language),
+				})
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleCreateGlossary(w http.ResponseWriter, r *http.Request) {
+	var req glossaryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	switch {
+	case req.Name == "":
+		writeError(w, http.StatusBadRequest, "Parameter 'name' not specified.")
+		return
+	case len(req.Dictionaries) == 0:
+		writeError(w, http.StatusBadRequest, "Parameter 'dictionaries' not specified.")
+		return
+	}
+
+	g := &glossary{name: req.Name, created: time.Now().UTC()}
+	for _, rd := range req.Dictionaries {
+		d, msg := s.newDictionary(rd)
+		if msg != "" {
+			writeError(w, http.StatusBadRequest, msg)
+			return
+		}
+		g.put(d)
+	}
+
+	s.mu.Lock()
+	g.id = strings.ToLower(s.newID())
+	s.glossaries[g.id] = g
+	resp := g.json()
+	s.mu.Unlock()
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (s *Server) handleListGlossaries(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	resp := struct {
+		Glossaries []glossaryJSON `json:"glossaries"`
+	}{Glossaries: []glossaryJSON{}}
+	for _, g := range s.glossaries {
+		resp.Glossaries = append(resp.Glossaries, g.json())
+	}
+	s.mu.Unlock()
+
+	sort.Slice(resp.Glossaries, func(i, j int) bool {
+		return resp.Glossaries[i].GlossaryID < resp.Glossaries[j].GlossaryID
+	})
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleGetGlossary(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.glossaries[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Glossary not found.")
+		return
+	}
+	writeJSON(w, http.StatusOK, g.json())
+}
+
+func (s *Server) handleEditGlossary(w http.ResponseWriter, r *http.Request) {
+	var req glossaryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	switch {
+	case req.Name == "" && len(req.Dictionaries) == 0:
+		writeError(w, http.StatusBadRequest, "Either 'name' or 'dictionaries' must be specified.")
+		return
+	case len(req.Dictionaries) > 1:
+		writeError(w, http.StatusBadRequest, "Only one dictionary can be edited at a time.")
+		return
+	}
+	dictionaries := make([]*dictionary, len(req.Dictionaries))
+	for i, rd := range req.Dictionaries {
+		var msg string
+		if dictionaries[i], msg = s.newDictionary(rd); msg != "" {
+			writeError(w, http.StatusBadRequest, msg)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.glossaries[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Glossary not found.")
+		return
+	}
+	if req.Name != "" {
+		g.name = req.Name
+	}
+	for _, d := range dictionaries {
+		g.put(d)
+	}
+	writeJSON(w, http.StatusOK, g.json())
+}
+
+func (s *Server) handleDeleteGlossary(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := r.PathValue("id")
+	if _, ok := s.glossaries[id]; !ok {
+		writeError(w, http.StatusNotFound, "Glossary not found.")
+		return
+	}
+	delete(s.glossaries, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleGlossaryEntries(w http.ResponseWriter, r *http.Request) {
+	sourceLang, targetLang, ok := langPairQuery(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.glossaries[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Glossary not found.")
+		return
+	}
+	d := g.dictionary(sourceLang, targetLang)
+	if d == nil {
+		writeError(w, http.StatusNotFound, "Dictionary not found.")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]dictionaryJSON{"dictionaries": {d.json(true)}})
+}
+
+func (s *Server) handlePutDictionary(w http.ResponseWriter, r *http.Request) {
+	var req dictionaryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	d, msg := s.newDictionary(req)
+	if msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.glossaries[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Glossary not found.")
+		return
+	}
+	g.put(d)
+	writeJSON(w, http.StatusOK, d.json(false))
+}
+
+func (s *Server) handleDeleteDictionary(w http.ResponseWriter, r *http.Request) {
+	sourceLang, targetLang, ok := langPairQuery(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.glossaries[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Glossary not found.")
+		return
+	}
+	target := g.dictionary(sourceLang, targetLang)
+	if target == nil {
+		writeError(w, http.StatusNotFound, "Dictionary not found.")
+		return
+	}
+	for i, d := range g.dictionaries {
+		if d == target {
+			g.dictionaries = append(g.dictionaries[:i], g.dictionaries[i+1:]...)
+			break
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// langPairQuery reads the source_lang and target_lang query parameters.
+func langPairQuery(w http.ResponseWriter, r *http.Request) (models.Language, models.Language, bool) {
+	query := r.URL.Query()
+	sourceLang, targetLang := query.Get("source_lang"), query.Get("target_lang")
+	switch {
+	case sourceLang == "":
+		writeError(w, http.StatusBadRequest, "Parameter 'source_lang' not specified.")
+		return "", "", false
+	case targetLang == "":
+		writeError(w, http.StatusBadRequest, "Parameter 'target_lang' not specified.")
+		return "", "", false
+	}
+	return models.Language(sourceLang), models.Language(targetLang), true
+}
@@ -0,0 +1,341 @@
+// Package deepltest provides an in-process DeepL API server for tests. It
+// implements text translation, document translation, usage, languages, text
+// improvement and the v3 glossary endpoints, so that code using
+// godeeplapi.Client can be tested without network access or an API key:
+//
+//	srv := deepltest.NewServer()
+//	defer srv.Close()
+//	client := srv.Client()
+//
+// Requests are validated like the real API and billed against a character
+// quota. Latency and errors can be injected per endpoint.
+//
+// Cassette records interactions with the real API and replays them, for
+// integration tests that run in CI without an API key.
+package deepltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/models"
+	"golang.org/x/text/language/display"
+)
+
+// DefaultAuthKey is the API key accepted by a server without WithAuthKey.
+const DefaultAuthKey = "deepltest-key:fx"
+
+// DefaultCharacterLimit is the character quota of a server without
+// WithCharacterLimit, the same as a free account.
+const DefaultCharacterLimit = 500000
+
+// TranslateFunc translates a single text. sourceLang is empty when the
+// request did not set it.
+type TranslateFunc func(text string, sourceLang, targetLang models.Language) string
+
+// Server is a fake DeepL API. It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	authKey          string
+	latency          time.Duration
+	translate        TranslateFunc
+	sourceLanguages  []models.SupportedLanguage
+	targetLanguages  []models.SupportedLanguage
+	queuedPolls      int
+	translatingPolls int
+
+	mu         sync.Mutex
+	charCount  int64
+	charLimit  int64
+	faults     []*Fault
+	requests   map[string]int
+	documents  map[string]*document
+	glossaries map[string]*glossary
+	nextID     int
+	docError   string
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithAuthKey sets the API key the server accepts.
+func WithAuthKey(key string) Option {
+	return func(s *Server) {
+		s.authKey = key
+	}
+}
+
+// WithLatency delays every response by d.
+func WithLatency(d time.Duration) Option {
+	return func(s *Server) {
+		s.latency = d
+	}
+}
+
+// WithCharacterLimit sets the character quota. Requests that would exceed
+// it fail with status 456.
+func WithCharacterLimit(limit int64) Option {
+	return func(s *Server) {
+		s.charLimit = limit
+	}
+}
+
+// WithTranslateFunc sets how texts are translated. By default a text is
+// prefixed with the lower-case target language, e.g. "de:Hello", unless a
+// glossary of the request has an entry for it.
+func WithTranslateFunc(fn TranslateFunc) Option {
+	return func(s *Server) {
+		s.translate = fn
+	}
+}
+
+// WithLanguages sets the lists returned by /languages and used to validate
+// requests. By default they are built from models.SourceLanguage and
+// models.TargetLanguage.
+func WithLanguages(source, target []models.SupportedLanguage) Option {
+	return func(s *Server) {
+		s.sourceLanguages = source
+		s.targetLanguages = target
+	}
+}
+
+// WithDocumentPolls makes an uploaded document report "queued" for the
+// first queued status checks and "translating" for the next translating
+// ones before it is done. By default it is done at the first check.
+func WithDocumentPolls(queued, translating int) Option {
+	return func(s *Server) {
+		s.queuedPolls = queued
+		s.translatingPolls = translating
+	}
+}
+
+// NewServer starts a server. Close it when done.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		authKey:    DefaultAuthKey,
+		charLimit:  DefaultCharacterLimit,
+		requests:   make(map[string]int),
+		documents:  make(map[string]*document),
+		glossaries: make(map[string]*glossary),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.sourceLanguages == nil {
+		s.sourceLanguages = builtinLanguages(models.SourceLanguage, false)
+	}
+	if s.targetLanguages == nil {
+		s.targetLanguages = builtinLanguages(models.TargetLanguage, true)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v2/translate", s.handleTranslate)
+	mux.HandleFunc("GET /v2/usage", s.handleUsage)
+	mux.HandleFunc("GET /v2/languages", s.handleLanguages)
+	mux.HandleFunc("POST /v2/write/rephrase", s.handleRephrase)
+	mux.HandleFunc("POST /v2/document", s.handleDocumentUpload)
+	mux.HandleFunc("POST /v2/document/{id}", s.handleDocumentStatus)
+	mux.HandleFunc("POST /v2/document/{id}/result", s.handleDocumentResult)
+	mux.HandleFunc("HEAD /v2/document/{id}/result", s.handleDocumentResult)
+	mux.HandleFunc("GET /{version}/glossary-language-pairs", s.handleGlossaryPairs)
+	mux.HandleFunc("POST /v3/glossaries", s.handleCreateGlossary)
+	mux.HandleFunc("GET /v3/glossaries", s.handleListGlossaries)
+	mux.HandleFunc("GET /v3/glossaries/{id}", s.handleGetGlossary)
+	mux.HandleFunc("PATCH /v3/glossaries/{id}", s.handleEditGlossary)
+	mux.HandleFunc("DELETE /v3/glossaries/{id}", s.handleDeleteGlossary)
+	mux.HandleFunc("GET /v3/glossaries/{id}/entries", s.handleGlossaryEntries)
+	mux.HandleFunc("PUT /v3/glossaries/{id}/dictionaries", s.handlePutDictionary)
+	mux.HandleFunc("DELETE /v3/glossaries/{id}/dictionaries", s.handleDeleteDictionary)
+
+	s.Server = httptest.NewServer(s.middleware(mux))
+	return s
+}
+
+// Client returns a v2 client for the server.
+func (s *Server) Client(opts ...godeeplapi.ClientOption) *godeeplapi.Client {
+	return godeeplapi.NewClient(s.authKey, false, append([]godeeplapi.ClientOption{godeeplapi.WithBaseURL(s.URL + "/v2")}, opts...)...)
+}
+
+// ClientV3 returns a v3 client for the server, for the glossary endpoints.
+func (s *Server) ClientV3(opts ...godeeplapi.ClientOption) *godeeplapi.Client {
+	return godeeplapi.NewClientV3(s.authKey, false, append([]godeeplapi.ClientOption{godeeplapi.WithBaseURL(s.URL + "/v3")}, opts...)...)
+}
+
+// Usage returns the billed characters and the character quota.
+func (s *Server) Usage() (count, limit int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.charCount, s.charLimit
+}
+
+// SetUsage sets the billed characters and the character quota.
+func (s *Server) SetUsage(count, limit int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.charCount, s.charLimit = count, limit
+}
+
+// Requests returns the number of requests received for a path without the
+// version, e.g. "/translate". Rejected requests are counted too.
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+// FailDocuments makes documents uploaded from now on end in the "error"
+// status with message. An empty message turns it off.
+func (s *Server) FailDocuments(message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.docError = message
+}
+
+// Fault is an error injected by Inject.
+type Fault struct {
+	// Method and Path select the requests, e.g. "POST" and "/translate".
+	// Path is matched as a prefix of the path without the version. Empty
+	// values match every request.
+	Method string
+	Path   string
+	// Status is the response status, e.g. http.StatusTooManyRequests.
+	Status int
+	// Message is the error message of the response body.
+	Message string
+	// Times is how often the fault occurs. Zero means until ClearFaults.
+	Times int
+}
+
+// Inject makes matching requests fail. Faults are checked in the order they
+// were injected.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// middleware counts requests, applies the latency and the injected faults and
+// checks the API key.
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := apiPath(r.URL.Path)
+
+		s.mu.Lock()
+		s.requests[path]++
+		fault := s.fault(r.Method, path)
+		s.mu.Unlock()
+
+		if s.latency > 0 {
+			select {
+			case <-time.After(s.latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if fault != nil {
+			writeError(w, fault.Status, fault.Message)
+			return
+		}
+		if r.Header.Get("Authorization") != "DeepL-Auth-Key "+s.authKey {
+			writeError(w, http.StatusForbidden, "Wrong endpoint or invalid authentication key")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// apiPath strips the version from a request path, e.g. "/v2/translate"
+// becomes "/translate".
+func apiPath(path string) string {
+	if i := strings.Index(path[1:], "/"); i >= 0 {
+		return path[i+1:]
+	}
+	return path
+}
+
+// fault returns the first matching fault and uses it up. s.mu must be held.
+func (s *Server) fault(method, path string) *Fault {
+	for i, f := range s.faults {
+		if (f.Method != "" && f.Method != method) || !strings.HasPrefix(path, f.Path) {
+			continue
+		}
+		if f.Times > 0 {
+			if f.Times--; f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+// bill adds chars to the billed characters, or fails when the quota would be
+// exceeded.
+func (s *Server) bill(chars int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.charLimit > 0 && s.charCount+chars > s.charLimit {
+		return false
+	}
+	s.charCount += chars
+	return true
+}
+
+// newID returns a new upper-case hex ID.
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("%032X", s.nextID)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	writeJSON(w, status, map[string]string{"message": message})
+}
+
+// builtinLanguages lists the fields of models.SourceLanguage or
+// models.TargetLanguage.
+func builtinLanguages(list any, target bool) []models.SupportedLanguage {
+	v := reflect.ValueOf(list)
+	out := make([]models.SupportedLanguage, 0, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		code := v.Field(i).Interface().(models.Language)
+		out = append(out, models.SupportedLanguage{
+			Language:          code.String(),
+			Name:              display.English.Tags().Name(code.Tag()),
+			SupportsFormality: target && code.SupportsFormality(),
+		})
+	}
+	return out
+}
+
+func findLanguage(languages []models.SupportedLanguage, lang string) bool {
+	for _, l := range languages {
+		if strings.EqualFold(l.Language, lang) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package deepltest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+// maxRequestSize is the request size limit of /translate.
+const maxRequestSize = 128 << 10
+
+// maxTexts is the number of texts a /translate request may contain.
+const maxTexts = 50
+
+// translateRequest is the body of /translate as sent on the wire.
+type translateRequest struct {
+	Text                 []string `json:"text"`
+	SourceLang           string   `json:"source_lang"`
+	TargetLang           string   `json:"target_lang"`
+	ShowBilledCharacters bool     `json:"show_billed_characters"`
+	SplitSentences       string   `json:"split_sentences"`
+	Formality            string   `json:"formality"`
+	ModelType            string   `json:"model_type"`
+	GlossaryID           string   `json:"glossary_id"`
+	TagHandling          string   `json:"tag_handling"`
+	NonSplittingTags     []string `json:"non_splitting_tags"`
+	SplittingTags        []string `json:"splitting_tags"`
+	IgnoreTags           []string `json:"ignore_tags"`
+}
+
+type translation struct {
+	DetectedSourceLanguage string `json:"detected_source_language"`
+	Text                   string `json:"text"`
+	BilledCharacters       int64  `json:"billed_characters,omitempty"`
+}
+
+func (s *Server) handleTranslate(w http.ResponseWriter, r *http.Request) {
+	var req translateRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "Request size exceeds the limit")
+			return
+		}
+		writeError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+
+	if msg := s.checkTranslate(req); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+	sourceLang, targetLang := models.Language(req.SourceLang), models.Language(req.TargetLang)
+
+	var entries map[string]string
+	if req.GlossaryID != "" {
+		var status int
+		var msg string
+		if entries, status, msg = s.glossaryEntries(req.GlossaryID, sourceLang, targetLang); msg != "" {
+			writeError(w, status, msg)
+			return
+		}
+	}
+
+	if !s.bill(godeeplapi.EstimateCharacters(models.TranslationRequest{Text: req.Text, TagHandling: req.TagHandling})) {
+		writeError(w, godeeplapi.ErrQuotaExceeded.StatusCode, "Quota exceeded")
+		return
+	}
+
+	detected := sourceLang
+	if detected == "" {
+		detected = "EN"
+	}
+	resp := struct {
+		Translations []translation `json:"translations"`
+	}{Translations: make([]translation, len(req.Text))}
+	for i, text := range req.Text {
+		t := translation{DetectedSourceLanguage: strings.ToUpper(detected.String()), Text: s.translateText(text, sourceLang, targetLang, entries)}
+		if req.ShowBilledCharacters {
+			t.BilledCharacters = godeeplapi.EstimateCharacters(models.TranslationRequest{Text: []string{text}, TagHandling: req.TagHandling})
+		}
+		resp.Translations[i] = t
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// translateText translates a text with the glossary entries or the
+// TranslateFunc.
+func (s *Server) translateText(text string, sourceLang, targetLang models.Language, entries map[string]string) string {
+	if translated, ok := entries[text]; ok {
+		return translated
+	}
+	if s.translate != nil {
+		return s.translate(text, sourceLang, targetLang)
+	}
+	return strings.ToLower(targetLang.String()) + ":" + text
+}
+
+func (s *Server) handleUsage(w http.ResponseWriter, r *http.Request) {
+	count, limit := s.Usage()
+	writeJSON(w, http.StatusOK, models.UsageAndLimitResponse{CharCount: count, CharLimit: limit})
+}
+
+func (s *Server) handleLanguages(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Query().Get("type") {
+	case "", models.LanguageTypeSource:
+		writeJSON(w, http.StatusOK, s.sourceLanguages)
+	case models.LanguageTypeTarget:
+		writeJSON(w, http.StatusOK, s.targetLanguages)
+	default:
+		writeError(w, http.StatusBadRequest, "Value for 'type' not supported.")
+	}
+}
+
+// rephraseRequest is the body of /write/rephrase as sent on the wire.
+type rephraseRequest struct {
+	Text         []string `json:"text"`
+	TargetLang   string   `json:"target_lang"`
+	WritingStyle string   `json:"writing_style"`
+	Tone         string   `json:"tone"`
+}
+
+// improvement is one entry of the /write/rephrase response.
+type improvement struct {
+	Text                   string `json:"text"`
+	TargetLanguage         string `json:"target_language"`
+	DetectedSourceLanguage string `json:"detected_source_language"`
+}
+
+// writeLanguages, writingStyles and tones are the values /write/rephrase
+// accepts.
+var (
+	writeLanguages = []string{"de", "en", "en-GB", "en-US", "es", "fr", "it", "pt", "pt-BR", "pt-PT"}
+	writingStyles  = []string{"default", "simple", "business", "academic", "casual",
+		"prefer_simple", "prefer_business", "prefer_academic", "prefer_casual"}
+	tones = []string{"default", "enthusiastic", "friendly", "confident", "diplomatic",
+		"prefer_enthusiastic", "prefer_friendly", "prefer_confident", "prefer_diplomatic"}
+)
+
+func (s *Server) handleRephrase(w http.ResponseWriter, r *http.Request) {
+	var req rephraseRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+
+	switch {
+	case len(req.Text) == 0:
+		writeError(w, http.StatusBadRequest, "Parameter 'text' not specified.")
+		return
+	case len(req.Text) > maxTexts:
+		writeError(w, http.StatusBadRequest, "Too many texts in request.")
+		return
+	case req.TargetLang != "" && !containsFold(writeLanguages, req.TargetLang):
+		writeError(w, http.StatusBadRequest, "Value for 'target_lang' not supported.")
+		return
+	case req.WritingStyle != "" && !slices.Contains(writingStyles, req.WritingStyle):
+		writeError(w, http.StatusBadRequest, "Value for 'writing_style' not supported.")
+		return
+	case req.Tone != "" && !slices.Contains(tones, req.Tone):
+		writeError(w, http.StatusBadRequest, "Value for 'tone' not supported.")
+		return
+	case req.WritingStyle != "" && req.Tone != "":
+		writeError(w, http.StatusBadRequest, "Only one of 'writing_style' and 'tone' can be set.")
+		return
+	}
+
+	target := req.TargetLang
+	if target == "" {
+		target = "en-US"
+	}
+	source, _, _ := strings.Cut(strings.ToLower(target), "-")
+	resp := struct {
+		Improvements []improvement `json:"improvements"`
+	}{Improvements: make([]improvement, len(req.Text))}
+	for i, text := range req.Text {
+		resp.Improvements[i] = improvement{Text: improve(text), TargetLanguage: target, DetectedSourceLanguage: source}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// improve collapses runs of white space and capitalizes the first letter.
+func improve(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return text
+	}
+	r, size := utf8.DecodeRuneInString(text)
+	return string(unicode.ToUpper(r)) + text[size:]
+}
@@ -0,0 +1,119 @@
+package deepltest
+
+import (
+	"slices"
+	"strings"
+)
+
+// The parameter values the API accepts. The server checks requests on its
+// own instead of with the Validate methods of the client's models, so that
+// tests catch requests the client gets wrong.
+var (
+	formalities    = []string{"default", "more", "less", "prefer_more", "prefer_less"}
+	tagHandlings   = []string{"xml", "html"}
+	splitSentences = []string{"0", "1", "nonewlines"}
+	modelTypes     = []string{"quality_optimized", "prefer_quality_optimized", "latency_optimized"}
+	entriesFormats = []string{"tsv", "csv"}
+)
+
+// checkTranslate returns an error message if the API rejects a /translate
+// request.
+func (s *Server) checkTranslate(req translateRequest) string {
+	switch {
+	case len(req.Text) == 0:
+		return "Parameter 'text' not specified."
+	case len(req.Text) > maxTexts:
+		return "Too many texts in request."
+	}
+	if msg := s.checkTarget(req.SourceLang, req.TargetLang, req.Formality, req.GlossaryID); msg != "" {
+		return msg
+	}
+
+	switch {
+	case req.TagHandling != "" && !slices.Contains(tagHandlings, req.TagHandling):
+		return "Value for 'tag_handling' not supported."
+	case req.TagHandling == "" && len(req.IgnoreTags) > 0:
+		return "Parameter 'ignore_tags' requires 'tag_handling'."
+	case req.TagHandling == "" && len(req.SplittingTags) > 0:
+		return "Parameter 'splitting_tags' requires 'tag_handling'."
+	case req.TagHandling == "" && len(req.NonSplittingTags) > 0:
+		return "Parameter 'non_splitting_tags' requires 'tag_handling'."
+	case req.SplitSentences != "" && !slices.Contains(splitSentences, req.SplitSentences):
+		return "Value for 'split_sentences' not supported."
+	case req.ModelType != "" && !slices.Contains(modelTypes, req.ModelType):
+		return "Value for 'model_type' not supported."
+	}
+	return ""
+}
+
+// checkTarget returns an error message if the API rejects the languages,
+// formality or glossary of a text or document translation.
+func (s *Server) checkTarget(sourceLang, targetLang, formality, glossaryID string) string {
+	if targetLang == "" {
+		return "Parameter 'target_lang' not specified."
+	}
+	if sourceLang != "" && !findLanguage(s.sourceLanguages, sourceLang) {
+		return "Value for 'source_lang' not supported."
+	}
+	if !findLanguage(s.targetLanguages, targetLang) {
+		return "Value for 'target_lang' not supported."
+	}
+
+	switch {
+	case formality != "" && !slices.Contains(formalities, formality):
+		return "Value for 'formality' not supported."
+	case (formality == "more" || formality == "less") && !s.supportsFormality(targetLang):
+		return "'formality' is not supported for given 'target_lang'."
+	case glossaryID != "" && sourceLang == "":
+		return "Parameter 'source_lang' is required when using a glossary."
+	}
+	return ""
+}
+
+// supportsFormality reports whether the target language list marks lang as
+// supporting formality.
+func (s *Server) supportsFormality(lang string) bool {
+	for _, l := range s.targetLanguages {
+		if strings.EqualFold(l.Language, lang) {
+			return l.SupportsFormality
+		}
+	}
+	return false
+}
+
+// checkDictionary returns an error message if the API rejects a glossary
+// dictionary.
+func (s *Server) checkDictionary(d dictionaryRequest) string {
+	source, target := baseLanguage(d.SourceLang), baseLanguage(d.TargetLang)
+	switch {
+	case d.SourceLang == "":
+		return "Parameter 'source_lang' not specified."
+	case d.TargetLang == "":
+		return "Parameter 'target_lang' not specified."
+	case !findLanguage(s.sourceLanguages, source) || !findLanguage(s.sourceLanguages, target):
+		return "Unsupported glossary language pair " + d.SourceLang + " to " + d.TargetLang + "."
+	case source == target:
+		return "Source and target language of a glossary must differ."
+	case strings.TrimSpace(d.Entries) == "":
+		return "Parameter 'entries' not specified."
+	case d.EntriesFormat != "" && !slices.Contains(entriesFormats, d.EntriesFormat):
+		return "Value for 'entries_format' not supported."
+	}
+	return ""
+}
+
+// baseLanguage reduces a language code to its upper-case base code, e.g.
+// "en-US" to "EN". Glossaries only use base codes.
+func baseLanguage(lang string) string {
+	base, _, _ := strings.Cut(lang, "-")
+	return strings.ToUpper(base)
+}
+
+func containsFold(list []string, value string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,351 @@
+package godeeplapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+// UploadDocument uploads a file for translation and returns its handle.
+// Store the handle to check the status and download the result later, even
+// from another process.
+func (c *Client) UploadDocument(ctx context.Context, req models.FileTranslationRequest) (*models.DocumentHandle, error) {
+	if c.authKey == "" {
+		return nil, fmt.Errorf("DeepL API token is empty")
+	}
+
+	req.SourceLang = req.SourceLang.SourceCode()
+	req.TargetLang = req.TargetLang.TargetCode()
+	if err := req.ValidateWith(c.languageSupport(ctx)); err != nil {
+		return nil, err
+	}
+
+	respBody, err := c.uploadFile(ctx, "/document", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response models.DocumentResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %w", err)
+	}
+	if response.DocumentId == "" || response.DocumentKey == "" {
+		return nil, fmt.Errorf("no document ID or key in response")
+	}
+
+	c.logger.Info("Uploaded document %s", response.DocumentId)
+	return &models.DocumentHandle{
+		DocumentId:   response.DocumentId,
+		DocumentKey:  response.DocumentKey,
+		FileName:     req.FileName,
+		TargetLang:   req.TargetLang,
+		OutputFormat: req.OutputFormat,
+		OnProgress:   req.OnProgress,
+	}, nil
+}
+
+// GetDocumentStatus returns the translation status of an uploaded document.
+func (c *Client) GetDocumentStatus(ctx context.Context, handle models.DocumentHandle) (*models.DocumentStatusResponse, error) {
+	if err := c.checkAuth(); err != nil {
+		return nil, err
+	}
+	requestBody := map[string]string{"document_key": handle.DocumentKey}
+
+	respBody, err := c.doRequest(ctx, "POST", "/document/"+handle.DocumentId, requestBody, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var status models.DocumentStatusResponse
+	if err := json.Unmarshal(respBody, &status); err != nil {
+		return nil, fmt.Errorf("error unmarshaling status response: %w", err)
+	}
+
+	return &status, nil
+}
+
+// PollStrategy controls how WaitForDocument checks the document status.
+// Zero fields take their value from DefaultPollStrategy.
+type PollStrategy struct {
+	// MinInterval and MaxInterval bound the time between two checks.
+	MinInterval time.Duration
+	MaxInterval time.Duration
+	// Backoff multiplies the interval after each check without an estimate
+	// from DeepL. Values below 1 mean 1.
+	Backoff float64
+	// MaxQueued fails with ErrDocumentStuck when a document stays queued
+	// longer. A negative value disables the check.
+	MaxQueued time.Duration
+	// Timeout limits the whole wait in addition to the deadline of ctx. A
+	// negative value means only ctx.
+	Timeout time.Duration
+}
+
+// DefaultPollStrategy checks every second at first and then less often,
+// follows the estimate of DeepL while translating, and gives up on documents
+// that stay queued for 30 minutes. The wait is only limited by ctx.
+var DefaultPollStrategy = PollStrategy{
+	MinInterval: time.Second,
+	MaxInterval: 30 * time.Second,
+	Backoff:     1.5,
+	MaxQueued:   30 * time.Minute,
+	Timeout:     -1,
+}
+
+// withDefaults fills zero fields from DefaultPollStrategy.
+func (p PollStrategy) withDefaults() PollStrategy {
+	if p.MinInterval <= 0 {
+		p.MinInterval = DefaultPollStrategy.MinInterval
+	}
+	if p.MaxInterval <= 0 {
+		p.MaxInterval = DefaultPollStrategy.MaxInterval
+	}
+	if p.MaxInterval < p.MinInterval {
+		p.MaxInterval = p.MinInterval
+	}
+	if p.Backoff == 0 {
+		p.Backoff = DefaultPollStrategy.Backoff
+	}
+	if p.Backoff < 1 {
+		p.Backoff = 1
+	}
+	if p.MaxQueued == 0 {
+		p.MaxQueued = DefaultPollStrategy.MaxQueued
+	}
+	if p.Timeout == 0 {
+		p.Timeout = DefaultPollStrategy.Timeout
+	}
+	return p
+}
+
+// clamp limits d to the interval bounds.
+func (p PollStrategy) clamp(d time.Duration) time.Duration {
+	return min(max(d, p.MinInterval), p.MaxInterval)
+}
+
+// WaitForDocument checks the status of a document until its translation is
+// done, as set up by WithPollStrategy. It fails if the translation fails,
+// the document is stuck in the queue, or ctx is done.
+func (c *Client) WaitForDocument(ctx context.Context, handle models.DocumentHandle) (*models.DocumentStatusResponse, error) {
+	poll := c.pollStrategy.withDefaults()
+	if poll.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, poll.Timeout)
+		defer cancel()
+	}
+
+	interval := poll.MinInterval
+	var queuedSince time.Time
+	for {
+		status, err := c.GetDocumentStatus(ctx, handle)
+		if err != nil {
+			c.logger.Error("Error checking document status: %v", err)
+			return nil, err
+		}
+
+		if handle.OnProgress != nil {
+			handle.OnProgress(models.DocumentProgress{
+				Stage:            models.DocumentStage(status.DocumentStatus),
+				DocumentId:       handle.DocumentId,
+				SecondsRemaining: status.SecondsRemaining,
+				BilledCharacters: status.BilledChars,
+			})
+		}
+
+		var wait time.Duration
+		switch status.DocumentStatus {
+		case models.DocumentStatusDone:
+			return status, nil
+
+		case models.DocumentStatusTranslating:
+			queuedSince = time.Time{}
+			if status.SecondsRemaining > 0 {
+				wait = poll.clamp(time.Duration(status.SecondsRemaining) * time.Second)
+			} else {
+				wait = interval
+			}
+			c.logger.Debug("Document is translating, seconds remaining: %d. Checking again in %v",
+				status.SecondsRemaining, wait)
+
+		case models.DocumentStatusQueued:
+			if queuedSince.IsZero() {
+				queuedSince = time.Now()
+			} else if poll.MaxQueued > 0 && time.Since(queuedSince) > poll.MaxQueued {
+				c.logger.Error("Document %s is queued for more than %v", handle.DocumentId, poll.MaxQueued)
+				return nil, fmt.Errorf("%w: document %s is queued for more than %v", ErrDocumentStuck, handle.DocumentId, poll.MaxQueued)
+			}
+			wait = interval
+			c.logger.Debug("Document is queued for translation. Checking again in %v", wait)
+
+		case models.DocumentStatusError:
+			c.logger.Error("Document processing error: %s", status.ErrorMessage)
+			return nil, fmt.Errorf("document processing error: %s", status.ErrorMessage)
+
+		default:
+			c.logger.Error("Unknown document status: %s", status.DocumentStatus)
+			return nil, fmt.Errorf("unknown document status: %s", status.DocumentStatus)
+		}
+
+		if wait == interval {
+			interval = poll.clamp(time.Duration(float64(interval) * poll.Backoff))
+		}
+
+		select {
+		case <-time.After(wait):
+			// Continue checking
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// DownloadDocument downloads a translated document to targetDir and returns
+// its path. The file name is taken from the response, or else from the
+// handle, sanitized and formatted as set up by WithOutputOptions. The file
+// is written to a temporary file first and renamed when complete.
+//
+// DeepL deletes the document once the download starts, so the result is
+// lost if writing it fails afterwards, e.g. when the disk is full or, with
+// CollisionFail, when the name sent by DeepL differs from the expected one
+// and exists. Existing files and the target directory are checked before
+// the download to make this unlikely.
+func (c *Client) DownloadDocument(ctx context.Context, handle models.DocumentHandle, targetDir string) (string, error) {
+	// Create target directory if needed
+	if targetDir == "" {
+		targetDir = "."
+	}
+
+	// Fail before the download deletes the document at DeepL
+	if name := handle.ResultFileName(); c.output.Collision == CollisionFail && name != "" {
+		path := filepath.Join(targetDir, c.output.outputName(name, handle))
+		if _, err := os.Lstat(path); err == nil {
+			return "", fmt.Errorf("%w: %s", ErrOutputExists, path)
+		}
+	}
+
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return "", fmt.Errorf("error creating target directory: %w", err)
+	}
+	tmp, err := createOutput(targetDir)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	body, result, err := c.OpenDocument(ctx, handle)
+	if err != nil {
+		tmp.Close()
+		return "", err
+	}
+	defer body.Close()
+
+	outputPath, err := writeOutput(tmp, c.output.outputName(result.FileName, handle), c.output.Collision, body)
+	if err != nil {
+		return "", err
+	}
+
+	c.logger.Info("File successfully downloaded to: %s", outputPath)
+	return outputPath, nil
+}
+
+// DownloadDocumentTo writes a translated document to w, e.g. an object
+// storage upload or an HTTP response. The returned result has the number of
+// bytes written.
+func (c *Client) DownloadDocumentTo(ctx context.Context, handle models.DocumentHandle, w io.Writer) (*models.DocumentResult, error) {
+	body, result, err := c.OpenDocument(ctx, handle)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	result.Size, err = io.Copy(w, body)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading document: %w", err)
+	}
+	return result, nil
+}
+
+// DownloadDocumentBytes returns a translated document in memory.
+func (c *Client) DownloadDocumentBytes(ctx context.Context, handle models.DocumentHandle) ([]byte, *models.DocumentResult, error) {
+	var buf bytes.Buffer
+	result, err := c.DownloadDocumentTo(ctx, handle, &buf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), result, nil
+}
+
+// OpenDocument starts the download of a translated document. The caller
+// must close the returned body; ctx must not be done before that.
+func (c *Client) OpenDocument(ctx context.Context, handle models.DocumentHandle) (io.ReadCloser, *models.DocumentResult, error) {
+	if err := c.checkAuth(); err != nil {
+		return nil, nil, err
+	}
+	endpoint := "/document/" + handle.DocumentId + "/result"
+	requestBody := map[string]string{"document_key": handle.DocumentKey}
+
+	resp, err := c.openDownload(ctx, endpoint, requestBody)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := &models.DocumentResult{
+		FileName:    defaultFileName,
+		ContentType: resp.Header.Get("Content-Type"),
+		Size:        resp.ContentLength,
+	}
+	if name := handle.ResultFileName(); name != "" {
+		result.FileName = sanitizeFileName(name)
+	}
+	// Get filename from Content-Disposition header
+	if contentDisposition := resp.Header.Get("Content-Disposition"); contentDisposition != "" {
+		if _, params, err := mime.ParseMediaType(contentDisposition); err == nil {
+			if filename := sanitizeFileName(params["filename"]); filename != defaultFileName {
+				result.FileName = filename
+			}
+		}
+	}
+	if handle.OnProgress == nil {
+		return resp.Body, result, nil
+	}
+	return &downloadReader{
+		ReadCloser: resp.Body,
+		progress:   models.DocumentProgress{Stage: models.DocumentStageDownloading, DocumentId: handle.DocumentId, Total: resp.ContentLength},
+		report:     handle.OnProgress,
+	}, result, nil
+}
+
+// ResumeDocument waits for an uploaded document and downloads it to
+// targetDir, e.g. with a handle stored before a restart.
+func (c *Client) ResumeDocument(ctx context.Context, handle models.DocumentHandle, targetDir string) (string, error) {
+	if _, err := c.WaitForDocument(ctx, handle); err != nil {
+		return "", err
+	}
+
+	c.logger.Info("Document translation completed, downloading...")
+	return c.DownloadDocument(ctx, handle, targetDir)
+}
+
+// TranslateDocument translates the document read from file and returns the
+// translated document as a stream, without touching the disk. req.File is
+// ignored. The caller must close the returned body; ctx must not be done
+// before that.
+func (c *Client) TranslateDocument(ctx context.Context, file io.Reader, req models.FileTranslationRequest) (io.ReadCloser, *models.DocumentResult, error) {
+	req.File = file
+	handle, err := c.UploadDocument(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := c.WaitForDocument(ctx, *handle); err != nil {
+		return nil, nil, err
+	}
+	return c.OpenDocument(ctx, *handle)
+}
@@ -1,6 +1,9 @@
 package godeeplapi
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 type APIError struct {
 	StatusCode int
@@ -26,3 +29,15 @@ var (
 	ErrUnavailable   = &APIError{StatusCode: 503, Message: "Resource temporarily unavailable. Try again later."}
 	Err529TooMany    = &APIError{StatusCode: 529, Message: "Too many requests. Please wait and resend request"}
 )
+
+// ErrDocumentTooLarge is returned for documents that exceed the size limit
+// of their format, see models.DocumentSizeLimits.
+var ErrDocumentTooLarge = errors.New("document exceeds the size limit of its format")
+
+// ErrDocumentStuck is returned when a document stays queued for longer than
+// PollStrategy.MaxQueued.
+var ErrDocumentStuck = errors.New("document translation is stuck in the queue")
+
+// ErrOutputExists is returned by DownloadDocument when the output file
+// exists and OutputOptions.Collision is CollisionFail.
+var ErrOutputExists = errors.New("output file already exists")
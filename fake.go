@@ -0,0 +1,516 @@
+package godeeplapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+// Fake is an in-memory Service for unit tests. Requests are validated like
+// the client does, texts and documents are answered from Translations, and
+// glossaries and uploaded documents are kept in memory. The Func fields script individual methods. It is safe for
+// concurrent use.
+type Fake struct {
+	// Translations maps a source text to its translation. Texts without an
+	// entry are returned with a "<target>:" prefix, e.g. "de:Hello".
+	Translations map[string]string
+	// Err is returned by every call when set.
+	Err error
+
+	TranslateFunc     func(ctx context.Context, req models.TranslationRequest) ([]string, error)
+	ImproveTextFunc   func(ctx context.Context, req models.RephraseRequest) (string, error)
+	TranslateFileFunc func(ctx context.Context, req models.FileTranslationRequest, targetDir string) (string, error)
+
+	mu         sync.Mutex
+	glossaries map[string]*fakeGlossary
+	documents  map[string]*fakeDocument
+	nextID     int
+}
+
+var (
+	_ Translator         = (*Fake)(nil)
+	_ Improver           = (*Fake)(nil)
+	_ GlossaryManager    = (*Fake)(nil)
+	_ DocumentTranslator = (*Fake)(nil)
+	_ Service            = (*Fake)(nil)
+)
+
+// NewFake creates a Fake that answers texts from translations, which may be
+// nil.
+func NewFake(translations map[string]string) *Fake {
+	return &Fake{Translations: translations}
+}
+
+// Translate implements Translator.
+func (f *Fake) Translate(ctx context.Context, req models.TranslationRequest) ([]string, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if f.TranslateFunc != nil {
+		return f.TranslateFunc(ctx, req)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]string, len(req.Text))
+	for i, text := range req.Text {
+		out[i] = f.translate(req.TargetLang, text)
+	}
+	return out, nil
+}
+
+func (f *Fake) translate(targetLang models.Language, text string) string {
+	if translated, ok := f.Translations[text]; ok {
+		return translated
+	}
+	return strings.ToLower(targetLang.String()) + ":" + text
+}
+
+// ImproveText implements Improver. By default the first text is returned
+// unchanged.
+func (f *Fake) ImproveText(ctx context.Context, req models.RephraseRequest) (string, error) {
+	if f.Err != nil {
+		return "", f.Err
+	}
+	if f.ImproveTextFunc != nil {
+		return f.ImproveTextFunc(ctx, req)
+	}
+	if err := req.Validate(); err != nil {
+		return "", err
+	}
+	return req.Text[0], nil
+}
+
+// TranslateFile implements DocumentTranslator. By default the whole file is
+// translated as a single text and written to targetDir under its file name.
+func (f *Fake) TranslateFile(ctx context.Context, req models.FileTranslationRequest, targetDir string) (string, error) {
+	if f.Err != nil {
+		return "", f.Err
+	}
+	if f.TranslateFileFunc != nil {
+		return f.TranslateFileFunc(ctx, req, targetDir)
+	}
+
+	handle, err := f.UploadDocument(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return f.ResumeDocument(ctx, *handle, targetDir)
+}
+
+// TranslateDocument implements DocumentTranslator.
+func (f *Fake) TranslateDocument(ctx context.Context, file io.Reader, req models.FileTranslationRequest) (io.ReadCloser, *models.DocumentResult, error) {
+	req.File = file
+	handle, err := f.UploadDocument(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f.OpenDocument(ctx, *handle)
+}
+
+// UploadDocument implements DocumentTranslator. The whole file is
+// translated as a single text right away, so the document is done at once.
+func (f *Fake) UploadDocument(ctx context.Context, req models.FileTranslationRequest) (*models.DocumentHandle, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(req.File)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.documents == nil {
+		f.documents = make(map[string]*fakeDocument)
+	}
+	f.nextID++
+	d := &fakeDocument{
+		key:     fmt.Sprintf("fake-key-%d", f.nextID),
+		content: f.translate(req.TargetLang, string(data)),
+		chars:   utf8.RuneCount(data),
+	}
+	id := fmt.Sprintf("fake-document-%d", f.nextID)
+	f.documents[id] = d
+	return &models.DocumentHandle{
+		DocumentId:   id,
+		DocumentKey:  d.key,
+		FileName:     req.FileName,
+		TargetLang:   req.TargetLang,
+		OutputFormat: req.OutputFormat,
+		OnProgress:   req.OnProgress,
+	}, nil
+}
+
+// GetDocumentStatus implements DocumentTranslator. Uploaded documents are
+// always done.
+func (f *Fake) GetDocumentStatus(ctx context.Context, handle models.DocumentHandle) (*models.DocumentStatusResponse, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	d, err := f.document(handle)
+	if err != nil {
+		return nil, err
+	}
+	return &models.DocumentStatusResponse{
+		DocumentId:     handle.DocumentId,
+		DocumentStatus: models.DocumentStatusDone,
+		BilledChars:    d.chars,
+	}, nil
+}
+
+// WaitForDocument implements DocumentTranslator.
+func (f *Fake) WaitForDocument(ctx context.Context, handle models.DocumentHandle) (*models.DocumentStatusResponse, error) {
+	status, err := f.GetDocumentStatus(ctx, handle)
+	if err != nil {
+		return nil, err
+	}
+	if handle.OnProgress != nil {
+		handle.OnProgress(models.DocumentProgress{Stage: models.DocumentStageDone, DocumentId: handle.DocumentId, BilledCharacters: status.BilledChars})
+	}
+	return status, nil
+}
+
+// ResumeDocument implements DocumentTranslator.
+func (f *Fake) ResumeDocument(ctx context.Context, handle models.DocumentHandle, targetDir string) (string, error) {
+	if _, err := f.WaitForDocument(ctx, handle); err != nil {
+		return "", err
+	}
+	return f.DownloadDocument(ctx, handle, targetDir)
+}
+
+// OpenDocument implements DocumentTranslator. The document is deleted like
+// in the API, so it can only be downloaded once.
+func (f *Fake) OpenDocument(ctx context.Context, handle models.DocumentHandle) (io.ReadCloser, *models.DocumentResult, error) {
+	if f.Err != nil {
+		return nil, nil, f.Err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	d, err := f.document(handle)
+	if err != nil {
+		return nil, nil, err
+	}
+	delete(f.documents, handle.DocumentId)
+
+	result := &models.DocumentResult{FileName: defaultFileName, ContentType: "text/plain", Size: int64(len(d.content))}
+	if name := handle.ResultFileName(); name != "" {
+		result.FileName = sanitizeFileName(name)
+	}
+	return io.NopCloser(strings.NewReader(d.content)), result, nil
+}
+
+// DownloadDocument implements DocumentTranslator. The document is written
+// to targetDir under its file name.
+func (f *Fake) DownloadDocument(ctx context.Context, handle models.DocumentHandle, targetDir string) (string, error) {
+	data, result, err := f.DownloadDocumentBytes(ctx, handle)
+	if err != nil {
+		return "", err
+	}
+	if targetDir == "" {
+		targetDir = "."
+	}
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return "", fmt.Errorf("error creating target directory: %w", err)
+	}
+
+	path := filepath.Join(targetDir, result.FileName)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("error writing file: %w", err)
+	}
+	return path, nil
+}
+
+// DownloadDocumentTo implements DocumentTranslator.
+func (f *Fake) DownloadDocumentTo(ctx context.Context, handle models.DocumentHandle, w io.Writer) (*models.DocumentResult, error) {
+	body, result, err := f.OpenDocument(ctx, handle)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if result.Size, err = io.Copy(w, body); err != nil {
+		return nil, fmt.Errorf("error downloading document: %w", err)
+	}
+	return result, nil
+}
+
+// DownloadDocumentBytes implements DocumentTranslator.
+func (f *Fake) DownloadDocumentBytes(ctx context.Context, handle models.DocumentHandle) ([]byte, *models.DocumentResult, error) {
+	var buf bytes.Buffer
+	result, err := f.DownloadDocumentTo(ctx, handle, &buf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), result, nil
+}
+
+// ListLangPairsSupportedByGlossaries implements GlossaryManager. It returns
+// every pair of the built-in source languages.
+func (f *Fake) ListLangPairsSupportedByGlossaries(ctx context.Context) (*models.GlossaryListResponse, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	langs := []models.Language{"DE", "EN", "ES", "FR", "IT", "JA", "NL", "PL", "PT", "RU", "ZH"}
+	resp := &models.GlossaryListResponse{}
+	for _, source := range langs {
+		for _, target := range langs {
+			if source != target {
+				resp.SupportedLanguages = append(resp.SupportedLanguages, models.GlossaryLangPair{SourceLanguage: source, TargetLanguage: target})
+			}
+		}
+	}
+	return resp, nil
+}
+
+// CreateGlossary implements GlossaryManager.
+func (f *Fake) CreateGlossary(ctx context.Context, req models.CreateGlossaryRequest) (*models.CreateGlossaryResponse, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.glossaries == nil {
+		f.glossaries = make(map[string]*fakeGlossary)
+	}
+	f.nextID++
+	g := &fakeGlossary{
+		GlossaryID:   fmt.Sprintf("fake-glossary-%d", f.nextID),
+		Name:         req.Name,
+		Dictionaries: append([]models.Dictionary(nil), req.Dictionaries...),
+		CreatedAt:    time.Now().UTC().Format(time.RFC3339),
+	}
+	f.glossaries[g.GlossaryID] = g
+	return &models.CreateGlossaryResponse{Glossary: withoutEntries(g)}, nil
+}
+
+// ListAllGlossaries implements GlossaryManager. Entries are omitted like in
+// the API.
+func (f *Fake) ListAllGlossaries(ctx context.Context) (*models.AllGlossaryListResponse, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	resp := &models.AllGlossaryListResponse{}
+	for _, g := range f.glossaries {
+		resp.Glossaries = append(resp.Glossaries, withoutEntries(g))
+	}
+	sort.Slice(resp.Glossaries, func(i, j int) bool {
+		return resp.Glossaries[i].GlossaryID < resp.Glossaries[j].GlossaryID
+	})
+	return resp, nil
+}
+
+// GetGlossaryByID implements GlossaryManager.
+func (f *Fake) GetGlossaryByID(ctx context.Context, id string) (*models.Glossary, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	g, err := f.glossary(id)
+	if err != nil {
+		return nil, err
+	}
+	out := withoutEntries(g)
+	return &out, nil
+}
+
+// EditGlossary implements GlossaryManager.
+func (f *Fake) EditGlossary(ctx context.Context, id string, req models.EditGlossaryRequest) (*models.Glossary, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	g, err := f.glossary(id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != "" {
+		g.Name = req.Name
+	}
+	for _, d := range req.Dictionaries {
+		g.putDictionary(d)
+	}
+	out := withoutEntries(g)
+	return &out, nil
+}
+
+// DeleteGlossary implements GlossaryManager.
+func (f *Fake) DeleteGlossary(ctx context.Context, id string) error {
+	if f.Err != nil {
+		return f.Err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.glossary(id); err != nil {
+		return err
+	}
+	delete(f.glossaries, id)
+	return nil
+}
+
+// DeleteAllLangDictionaries implements GlossaryManager.
+func (f *Fake) DeleteAllLangDictionaries(ctx context.Context, id string, query models.GlossaryLangPair) error {
+	if f.Err != nil {
+		return f.Err
+	}
+	if err := query.Validate(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	g, err := f.glossary(id)
+	if err != nil {
+		return err
+	}
+	i := g.dictionaryIndex(query.SourceLanguage, query.TargetLanguage)
+	if i < 0 {
+		return ErrNotFound
+	}
+	g.Dictionaries = append(g.Dictionaries[:i], g.Dictionaries[i+1:]...)
+	return nil
+}
+
+// GetGlossaryEntries implements GlossaryManager.
+func (f *Fake) GetGlossaryEntries(ctx context.Context, id string, query models.GlossaryLangPair) (*models.GlossaryEntriesResponse, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	g, err := f.glossary(id)
+	if err != nil {
+		return nil, err
+	}
+	i := g.dictionaryIndex(query.SourceLanguage, query.TargetLanguage)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	return &models.GlossaryEntriesResponse{Dictionaries: []models.Dictionary{g.Dictionaries[i]}}, nil
+}
+
+// ReplaceOrCreateDictionaryInGlossary implements GlossaryManager.
+func (f *Fake) ReplaceOrCreateDictionaryInGlossary(ctx context.Context, id string, req models.Dictionary) (*models.EditOrCreateDictionaryInGlossaryResponse, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	g, err := f.glossary(id)
+	if err != nil {
+		return nil, err
+	}
+	g.putDictionary(req)
+	return &models.EditOrCreateDictionaryInGlossaryResponse{
+		SourceLanguage: req.SourceLanguage,
+		TargetLanguage: req.TargetLanguage,
+		EntryCount:     countEntries(req.Entries),
+	}, nil
+}
+
+func (f *Fake) document(handle models.DocumentHandle) (*fakeDocument, error) {
+	d, ok := f.documents[handle.DocumentId]
+	if !ok || d.key != handle.DocumentKey {
+		return nil, ErrNotFound
+	}
+	return d, nil
+}
+
+func (f *Fake) glossary(id string) (*fakeGlossary, error) {
+	g, ok := f.glossaries[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return g, nil
+}
+
+// fakeDocument is an uploaded document with its translation.
+type fakeDocument struct {
+	key     string
+	content string
+	chars   int
+}
+
+// fakeGlossary is a stored glossary including its entries.
+type fakeGlossary models.Glossary
+
+func (g *fakeGlossary) dictionaryIndex(source, target models.Language) int {
+	for i, d := range g.Dictionaries {
+		if d.SourceLanguage.SourceCode() == source.SourceCode() && d.TargetLanguage.SourceCode() == target.SourceCode() {
+			return i
+		}
+	}
+	return -1
+}
+
+func (g *fakeGlossary) putDictionary(d models.Dictionary) {
+	if i := g.dictionaryIndex(d.SourceLanguage, d.TargetLanguage); i >= 0 {
+		g.Dictionaries[i] = d
+		return
+	}
+	g.Dictionaries = append(g.Dictionaries, d)
+}
+
+func withoutEntries(g *fakeGlossary) models.Glossary {
+	out := models.Glossary(*g)
+	out.Dictionaries = make([]models.Dictionary, len(g.Dictionaries))
+	for i, d := range g.Dictionaries {
+		d.Entries = ""
+		out.Dictionaries[i] = d
+	}
+	return out
+}
+
+func countEntries(entries string) int {
+	n := 0
+	for _, line := range strings.Split(entries, "\n") {
+		if strings.TrimSpace(line) != "" {
+			n++
+		}
+	}
+	return n
+}
@@ -4,26 +4,38 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/AdolfZahid1/godeeplapi/models"
 	"io"
 	"mime/multipart"
 	"net/http"
-	"os"
 	"path/filepath"
 )
 
+// uploadFile streams req.File as a multipart form. Only the form fields are
+// kept in memory; the file is copied through a pipe while it is sent.
 func (c *Client) uploadFile(ctx context.Context, endpoint string, req models.FileTranslationRequest) ([]byte, error) {
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+	size := req.FileSize
+	if size <= 0 {
+		size = readerSize(req.File)
+	}
+	limit, hasLimit := models.DocumentSizeLimit(req.FileName)
+	if hasLimit && size > limit {
+		return nil, fmt.Errorf("%w: %s has %d bytes, the limit is %d", ErrDocumentTooLarge, req.FileName, size, limit)
+	}
+
+	// Write the form fields and the header of the file part
+	head := &bytes.Buffer{}
+	writer := multipart.NewWriter(head)
 
 	// Add text fields with error handling
 	fields := map[string]string{
-		"target_lang": req.TargetLang,
+		"target_lang": req.TargetLang.String(),
 	}
 
 	if req.SourceLang != "" {
-		fields["source_lang"] = req.SourceLang
+		fields["source_lang"] = req.SourceLang.String()
 	}
 	if req.FileName != "" {
 		fields["filename"] = req.FileName
@@ -45,26 +57,46 @@ func (c *Client) uploadFile(ctx context.Context, endpoint string, req models.Fil
 	}
 
 	// Create the file part
-	fileWriter, err := writer.CreateFormFile("file", filepath.Base(req.FileName))
-	if err != nil {
+	if _, err := writer.CreateFormFile("file", filepath.Base(req.FileName)); err != nil {
 		return nil, fmt.Errorf("error creating form file: %w", err)
 	}
+	tail := "\r\n--" + writer.Boundary() + "--\r\n"
 
-	// Use buffered copy for better performance
-	buf := make([]byte, 32*1024) // 32KB buffer
-	if _, err := io.CopyBuffer(fileWriter, req.File, buf); err != nil {
-		return nil, fmt.Errorf("error copying file data: %w", err)
+	file := &uploadReader{r: req.File, total: size}
+	if req.OnProgress != nil {
+		file.onProgress = func(sent, total int64) {
+			req.OnProgress(models.DocumentProgress{Stage: models.DocumentStageUploading, Bytes: sent, Total: total})
+		}
 	}
-
-	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("error closing multipart writer: %w", err)
+	if hasLimit {
+		file.limit = limit
 	}
 
+	pr, pw := io.Pipe()
+	copyErr := make(chan error, 1)
+	go func() {
+		_, err := pw.Write(head.Bytes())
+		if err == nil {
+			// Use buffered copy for better performance
+			buf := make([]byte, 32*1024) // 32KB buffer
+			_, err = io.CopyBuffer(pw, file, buf)
+		}
+		if err == nil {
+			_, err = io.WriteString(pw, tail)
+		}
+		pw.CloseWithError(err)
+		copyErr <- err
+	}()
+
 	// Create request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+endpoint, body)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+endpoint, pr)
 	if err != nil {
+		pr.Close()
 		return nil, fmt.Errorf("error creating request: %w", err)
 	}
+	if size >= 0 {
+		httpReq.ContentLength = int64(head.Len()) + size + int64(len(tail))
+	}
 
 	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
 	httpReq.Header.Set("Authorization", "DeepL-Auth-Key "+c.authKey)
@@ -73,6 +105,10 @@ func (c *Client) uploadFile(ctx context.Context, endpoint string, req models.Fil
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
+		// The transport has closed the pipe, so the copy ends
+		if err := <-copyErr; errors.Is(err, ErrDocumentTooLarge) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("error sending request: %w", err)
 	}
 	defer resp.Body.Close()
@@ -93,15 +129,60 @@ func (c *Client) uploadFile(ctx context.Context, endpoint string, req models.Fil
 	return respBody, nil
 }
 
-func (c *Client) downloadToFile(ctx context.Context, endpoint string, requestBody interface{}, outputPath string) error {
+// readerSize returns the number of bytes left in r, or -1 if it is unknown.
+func readerSize(r io.Reader) int64 {
+	switch r := r.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len())
+	case io.Seeker:
+		current, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		end, err := r.Seek(0, io.SeekEnd)
+		if err != nil {
+			return -1
+		}
+		if _, err := r.Seek(current, io.SeekStart); err != nil {
+			return -1
+		}
+		return end - current
+	}
+	return -1
+}
+
+// uploadReader reports the upload progress and stops at the size limit.
+type uploadReader struct {
+	r          io.Reader
+	total      int64
+	limit      int64
+	sent       int64
+	onProgress func(sent, total int64)
+}
+
+func (u *uploadReader) Read(p []byte) (int, error) {
+	n, err := u.r.Read(p)
+	u.sent += int64(n)
+	if u.limit > 0 && u.sent > u.limit {
+		return n, fmt.Errorf("%w: the limit is %d bytes", ErrDocumentTooLarge, u.limit)
+	}
+	if n > 0 && u.onProgress != nil {
+		u.onProgress(u.sent, u.total)
+	}
+	return n, err
+}
+
+// openDownload posts requestBody to endpoint and returns the response with
+// its body still open, so that large files can be streamed.
+func (c *Client) openDownload(ctx context.Context, endpoint string, requestBody interface{}) (*http.Response, error) {
 	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
-		return fmt.Errorf("error marshaling request: %w", err)
+		return nil, fmt.Errorf("error marshaling request: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return fmt.Errorf("error creating request: %w", err)
+		return nil, fmt.Errorf("error creating request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -111,39 +192,38 @@ func (c *Client) downloadToFile(ctx context.Context, endpoint string, requestBod
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("error sending request: %w", err)
+		return nil, fmt.Errorf("error sending request: %w", err)
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
 		body, _ := io.ReadAll(resp.Body)
-		return &APIError{
+		return nil, &APIError{
 			StatusCode: resp.StatusCode,
 			Message:    http.StatusText(resp.StatusCode),
 			Details:    string(body),
 		}
 	}
 
-	// Create output directory if it doesn't exist
-	if dir := filepath.Dir(outputPath); dir != "" {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("error creating output directory: %w", err)
-		}
-	}
+	return resp, nil
+}
 
-	// Create output file
-	out, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("error creating output file: %w", err)
-	}
-	defer out.Close()
+// downloadReader reports the download progress of a document.
+type downloadReader struct {
+	io.ReadCloser
+	progress models.DocumentProgress
+	report   func(models.DocumentProgress)
+}
 
-	// Use buffered download for performance
-	buf := make([]byte, 64*1024) // 64KB buffer
-	if _, err := io.CopyBuffer(out, resp.Body, buf); err != nil {
-		return fmt.Errorf("error saving file: %w", err)
+func (d *downloadReader) Read(p []byte) (int, error) {
+	n, err := d.ReadCloser.Read(p)
+	d.progress.Bytes += int64(n)
+	if n > 0 {
+		d.report(d.progress)
 	}
-
-	c.logger.Info("File successfully downloaded to: %s", outputPath)
-	return nil
+	if err == io.EOF && d.progress.Stage != models.DocumentStageDownloaded {
+		d.progress.Stage = models.DocumentStageDownloaded
+		d.report(d.progress)
+	}
+	return n, err
 }
@@ -0,0 +1,232 @@
+// Package android reads, translates and writes Android strings.xml resources.
+package android
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+// Kind is the type of a resource.
+type Kind int
+
+const (
+	String Kind = iota
+	Plurals
+	StringArray
+)
+
+// Item is an element of a plurals or string-array resource.
+type Item struct {
+	// Quantity is the plural category (plurals only).
+	Quantity string
+	// Value is the inner XML of the item, with Android escaping.
+	Value string
+}
+
+// Resource is a single <string>, <plurals> or <string-array> element.
+type Resource struct {
+	Kind Kind
+	Name string
+	// Translatable is false for resources marked translatable="false".
+	Translatable bool
+	// Value is the inner XML of a <string>, with Android escaping.
+	Value string
+	Items []Item
+	// Comments directly preceding the resource.
+	Comments []string
+}
+
+// File is a parsed strings.xml file.
+type File struct {
+	Resources []*Resource
+	// Attributes of the <resources> element, such as namespace declarations.
+	Attrs []xml.Attr
+}
+
+// Get returns the resource with the given name, or nil.
+func (f *File) Get(name string) *Resource {
+	for _, r := range f.Resources {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// Parse reads a strings.xml file.
+func Parse(r io.Reader) (*File, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("android: error reading file: %w", err)
+	}
+
+	f := &File{}
+	dec := xml.NewDecoder(bytes.NewReader(data))
+
+	var (
+		depth    int
+		cur      *Resource
+		comments []string
+		inner    int
+		quantity string
+	)
+
+	for {
+		start := int(dec.InputOffset())
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("android: %w", err)
+		}
+		end := int(dec.InputOffset())
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			switch {
+			case depth == 1 && t.Name.Local == "resources":
+				f.Attrs = t.Attr
+			case depth == 2:
+				kind, ok := kinds[t.Name.Local]
+				if !ok {
+					comments = nil
+					continue
+				}
+				cur = &Resource{
+					Kind:         kind,
+					Name:         attr(t, "name"),
+					Translatable: attr(t, "translatable") != "false",
+					Comments:     comments,
+				}
+				comments = nil
+				inner = end
+			case depth == 3 && cur != nil && t.Name.Local == "item":
+				quantity = attr(t, "quantity")
+				inner = end
+			}
+
+		case xml.EndElement:
+			switch {
+			case depth == 2 && cur != nil:
+				if cur.Kind == String {
+					cur.Value = string(data[inner:start])
+				}
+				f.Resources = append(f.Resources, cur)
+				cur = nil
+			case depth == 3 && cur != nil && t.Name.Local == "item":
+				cur.Items = append(cur.Items, Item{Quantity: quantity, Value: string(data[inner:start])})
+			}
+			depth--
+
+		case xml.Comment:
+			if depth == 1 {
+				comments = append(comments, strings.TrimSpace(string(t)))
+			}
+		}
+	}
+
+	return f, nil
+}
+
+var kinds = map[string]Kind{
+	"string":       String,
+	"plurals":      Plurals,
+	"string-array": StringArray,
+}
+
+func attr(t xml.StartElement, name string) string {
+	for _, a := range t.Attr {
+		if a.Name.Space == "" && a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// Write writes the file in strings.xml format.
+func (f *File) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	bw.WriteString("<resources")
+	for _, a := range f.Attrs {
+		name := a.Name.Local
+		if a.Name.Space != "" {
+			name = a.Name.Space + ":" + name
+		}
+		bw.WriteString(" " + name + `="`)
+		xml.EscapeText(bw, []byte(a.Value))
+		bw.WriteString(`"`)
+	}
+	bw.WriteString(">\n")
+
+	for _, r := range f.Resources {
+		for _, c := range r.Comments {
+			bw.WriteString("    <!-- " + c + " -->\n")
+		}
+
+		nameAttr := ` name="` + escapeAttr(r.Name) + `"`
+		if !r.Translatable {
+			nameAttr += ` translatable="false"`
+		}
+
+		switch r.Kind {
+		case String:
+			bw.WriteString("    <string" + nameAttr + ">" + r.Value + "</string>\n")
+		case Plurals:
+			bw.WriteString("    <plurals" + nameAttr + ">\n")
+			for _, item := range r.Items {
+				bw.WriteString(`        <item quantity="` + escapeAttr(item.Quantity) + `">` + item.Value + "</item>\n")
+			}
+			bw.WriteString("    </plurals>\n")
+		case StringArray:
+			bw.WriteString("    <string-array" + nameAttr + ">\n")
+			for _, item := range r.Items {
+				bw.WriteString("        <item>" + item.Value + "</item>\n")
+			}
+			bw.WriteString("    </string-array>\n")
+		}
+	}
+
+	bw.WriteString("</resources>\n")
+	return bw.Flush()
+}
+
+func escapeAttr(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// ValuesDir returns the resource folder for a DeepL target language code,
+// e.g. values-de or values-pt-rBR.
+func ValuesDir(lang models.Language) string {
+	base, region, _ := strings.Cut(strings.ToUpper(lang.String()), "-")
+	base = strings.ToLower(base)
+
+	switch base {
+	case "zh":
+		if region == "HANT" {
+			return "values-zh-rTW"
+		}
+		return "values-zh-rCN"
+	case "id":
+		// Android still resolves Indonesian through the legacy code.
+		base = "in"
+	case "he":
+		base = "iw"
+	}
+
+	if region == "" {
+		return "values-" + base
+	}
+	return "values-" + base + "-r" + region
+}
@@ -0,0 +1,143 @@
+package android
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/internal/placeholder"
+	"github.com/AdolfZahid1/godeeplapi/internal/plural"
+	"github.com/AdolfZahid1/godeeplapi/internal/textbatch"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+// protected matches everything that must reach the target unchanged: format
+// specifiers, escape sequences and <xliff:g> spans.
+var protected = regexp.MustCompile(placeholder.Printf.String() +
+	`|\\[nt\\]|\\u[0-9a-fA-F]{4}|<xliff:g[^>]*>.*?</xliff:g>|<xliff:g[^>]*/>`)
+
+var unescaper = strings.NewReplacer(`\'`, `'`, `\"`, `"`, `\@`, `@`, `\?`, `?`)
+
+// slot is a value of the output file waiting for its translation.
+type slot struct {
+	value  *string
+	quoted bool
+	values []string
+}
+
+// Translate returns a strings.xml for req.TargetLang containing the
+// translatable resources of source. Plurals get one item per plural category
+// of the target language. req is used as a template for every request.
+func Translate(ctx context.Context, t godeeplapi.Translator, source *File, req models.TranslationRequest) (*File, error) {
+	out := &File{Attrs: source.Attrs}
+
+	var slots []*slot
+	var texts []string
+	add := func(value *string) {
+		text, quoted := unquote(*value)
+		if strings.TrimSpace(text) == "" {
+			return
+		}
+		text, values := placeholder.ProtectMarkup(text, protected)
+		slots = append(slots, &slot{value: value, quoted: quoted, values: values})
+		texts = append(texts, unescaper.Replace(text))
+	}
+
+	for _, r := range source.Resources {
+		if !r.Translatable {
+			continue
+		}
+		res := &Resource{Kind: r.Kind, Name: r.Name, Translatable: true, Value: r.Value, Comments: r.Comments}
+
+		switch r.Kind {
+		case String:
+			out.Resources = append(out.Resources, res)
+			add(&res.Value)
+		case StringArray:
+			res.Items = append([]Item(nil), r.Items...)
+			out.Resources = append(out.Resources, res)
+			for i := range res.Items {
+				add(&res.Items[i].Value)
+			}
+		case Plurals:
+			res.Items = pluralItems(r.Items, req.TargetLang.String())
+			out.Resources = append(out.Resources, res)
+			for i := range res.Items {
+				add(&res.Items[i].Value)
+			}
+		}
+	}
+
+	if len(texts) == 0 {
+		return out, nil
+	}
+
+	req.TagHandling = models.TagXML
+	translated, err := textbatch.Translate(ctx, t, req, texts)
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range slots {
+		text := placeholder.RestoreMarkup(escape(translated[i]), s.values)
+		if s.quoted {
+			text = `"` + text + `"`
+		}
+		*s.value = text
+	}
+
+	return out, nil
+}
+
+// pluralItems maps the source items onto the plural categories of the
+// target language, falling back to the "other" form.
+func pluralItems(items []Item, lang string) []Item {
+	forms := make(map[string]string)
+	for _, item := range items {
+		forms[item.Quantity] = item.Value
+	}
+
+	var extra []string
+	if _, ok := forms["zero"]; ok {
+		extra = append(extra, "zero")
+	}
+
+	var out []Item
+	for _, category := range plural.Merge(plural.Categories(lang), extra) {
+		value, ok := forms[category]
+		if !ok {
+			value = forms["other"]
+		}
+		out = append(out, Item{Quantity: category, Value: value})
+	}
+	return out
+}
+
+// unquote strips the double quotes Android allows around a whole value.
+func unquote(value string) (string, bool) {
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) && !strings.HasSuffix(value, `\"`) {
+		return value[1 : len(value)-1], true
+	}
+	return value, false
+}
+
+// escape applies Android escaping to the text parts of a translated value,
+// leaving markup alone.
+func escape(markup string) string {
+	var b strings.Builder
+	inTag := false
+	for i, r := range markup {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>':
+			inTag = false
+		case !inTag && (r == '\'' || r == '"'):
+			b.WriteByte('\\')
+		case !inTag && i == 0 && (r == '@' || r == '?'):
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,210 @@
+// Package apple reads, translates and writes Apple .strings and .stringsdict
+// localization files.
+package apple
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+// StringsEntry is a "key" = "value"; pair of a .strings file.
+type StringsEntry struct {
+	// Comment directly preceding the entry, without the comment markers.
+	Comment string
+	Key     string
+	Value   string
+}
+
+// StringsFile is a parsed .strings file. Entries keep the order of the source file.
+type StringsFile struct {
+	Entries []*StringsEntry
+}
+
+// Get returns the value for a key.
+func (f *StringsFile) Get(key string) (string, bool) {
+	for _, e := range f.Entries {
+		if e.Key == key {
+			return e.Value, true
+		}
+	}
+	return "", false
+}
+
+// ParseStrings reads a UTF-8 encoded .strings file.
+func ParseStrings(r io.Reader) (*StringsFile, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("apple: error reading file: %w", err)
+	}
+	s := strings.TrimPrefix(string(data), "\ufeff")
+	if !utf8.ValidString(s) {
+		return nil, fmt.Errorf("apple: .strings file is not valid UTF-8")
+	}
+
+	p := &stringsParser{src: s}
+	f := &StringsFile{}
+	var comment string
+
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			break
+		}
+
+		switch {
+		case strings.HasPrefix(p.src[p.pos:], "/*"):
+			end := strings.Index(p.src[p.pos+2:], "*/")
+			if end < 0 {
+				return nil, p.errorf("unterminated comment")
+			}
+			comment = strings.TrimSpace(p.src[p.pos+2 : p.pos+2+end])
+			p.pos += end + 4
+		case strings.HasPrefix(p.src[p.pos:], "//"):
+			end := strings.IndexByte(p.src[p.pos:], '\n')
+			if end < 0 {
+				end = len(p.src) - p.pos
+			}
+			comment = strings.TrimSpace(p.src[p.pos+2 : p.pos+end])
+			p.pos += end
+		case p.src[p.pos] == '"':
+			key, err := p.quoted()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpace()
+			if !p.consume('=') {
+				return nil, p.errorf("expected '=' after key %q", key)
+			}
+			p.skipSpace()
+			value, err := p.quoted()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpace()
+			if !p.consume(';') {
+				return nil, p.errorf("expected ';' after value of %q", key)
+			}
+			f.Entries = append(f.Entries, &StringsEntry{Comment: comment, Key: key, Value: value})
+			comment = ""
+		default:
+			return nil, p.errorf("unexpected character %q", p.src[p.pos])
+		}
+	}
+
+	return f, nil
+}
+
+type stringsParser struct {
+	src string
+	pos int
+}
+
+func (p *stringsParser) errorf(format string, args ...interface{}) error {
+	line := strings.Count(p.src[:p.pos], "\n") + 1
+	return fmt.Errorf("apple: line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func (p *stringsParser) skipSpace() {
+	for p.pos < len(p.src) && strings.ContainsRune(" \t\r\n", rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *stringsParser) consume(c byte) bool {
+	if p.pos < len(p.src) && p.src[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *stringsParser) quoted() (string, error) {
+	if !p.consume('"') {
+		return "", p.errorf("expected quoted string")
+	}
+
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		p.pos++
+		switch c {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			if p.pos >= len(p.src) {
+				return "", p.errorf("unterminated escape sequence")
+			}
+			e := p.src[p.pos]
+			p.pos++
+			switch e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'U', 'u':
+				if p.pos+4 > len(p.src) {
+					return "", p.errorf("invalid unicode escape")
+				}
+				code, err := strconv.ParseUint(p.src[p.pos:p.pos+4], 16, 32)
+				if err != nil {
+					return "", p.errorf("invalid unicode escape")
+				}
+				b.WriteRune(rune(code))
+				p.pos += 4
+			default:
+				b.WriteByte(e)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// Write writes the file in .strings format.
+func (f *StringsFile) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for i, e := range f.Entries {
+		if i > 0 {
+			bw.WriteString("\n")
+		}
+		if e.Comment != "" {
+			bw.WriteString("/* " + e.Comment + " */\n")
+		}
+		bw.WriteString(quoteStrings(e.Key) + " = " + quoteStrings(e.Value) + ";\n")
+	}
+	return bw.Flush()
+}
+
+var stringsEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+
+func quoteStrings(s string) string {
+	return `"` + stringsEscaper.Replace(s) + `"`
+}
+
+// LprojDir returns the bundle folder for a DeepL target language code,
+// e.g. de.lproj or pt-BR.lproj.
+func LprojDir(lang models.Language) string {
+	base, region, _ := strings.Cut(strings.ToUpper(lang.String()), "-")
+	base = strings.ToLower(base)
+
+	switch {
+	case base == "zh" && region == "HANT":
+		return "zh-Hant.lproj"
+	case base == "zh":
+		return "zh-Hans.lproj"
+	case base == "en" && region == "US":
+		return "en.lproj"
+	case region == "":
+		return base + ".lproj"
+	}
+	return base + "-" + region + ".lproj"
+}
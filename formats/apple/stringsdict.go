@@ -0,0 +1,214 @@
+package apple
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Keys used by .stringsdict plural rules.
+const (
+	keyFormat         = "NSStringLocalizedFormatKey"
+	keySpecType       = "NSStringFormatSpecTypeKey"
+	keyValueType      = "NSStringFormatValueTypeKey"
+	pluralRuleSpecKey = "NSStringPluralRuleType"
+)
+
+// plistValue is a property list value that keeps the key order of dicts.
+type plistValue struct {
+	// kind is the element name: dict, array, string, integer, real, true, false, date or data.
+	kind   string
+	keys   []string
+	values []*plistValue
+	text   string
+}
+
+func (v *plistValue) get(key string) *plistValue {
+	if v == nil || v.kind != "dict" {
+		return nil
+	}
+	for i, k := range v.keys {
+		if k == key {
+			return v.values[i]
+		}
+	}
+	return nil
+}
+
+func (v *plistValue) set(key string, value *plistValue) {
+	for i, k := range v.keys {
+		if k == key {
+			v.values[i] = value
+			return
+		}
+	}
+	v.keys = append(v.keys, key)
+	v.values = append(v.values, value)
+}
+
+// StringsDict is a parsed .stringsdict property list.
+type StringsDict struct {
+	root *plistValue
+}
+
+// Keys returns the localized string keys in file order.
+func (d *StringsDict) Keys() []string {
+	return append([]string(nil), d.root.keys...)
+}
+
+// Lookup returns the string at a path of dict keys, e.g.
+// Lookup("files", "count", "one").
+func (d *StringsDict) Lookup(path ...string) (string, bool) {
+	v := d.root
+	for _, key := range path {
+		v = v.get(key)
+	}
+	if v == nil || v.kind != "string" {
+		return "", false
+	}
+	return v.text, true
+}
+
+// ParseStringsDict reads a .stringsdict file in XML property list format.
+func ParseStringsDict(r io.Reader) (*StringsDict, error) {
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("apple: error decoding stringsdict: %w", err)
+		}
+		if start, ok := tok.(xml.StartElement); ok && start.Name.Local != "plist" {
+			v, err := decodePlist(dec, start)
+			if err != nil {
+				return nil, fmt.Errorf("apple: error decoding stringsdict: %w", err)
+			}
+			if v.kind != "dict" {
+				return nil, fmt.Errorf("apple: stringsdict root must be a dict")
+			}
+			return &StringsDict{root: v}, nil
+		}
+	}
+}
+
+func decodePlist(dec *xml.Decoder, start xml.StartElement) (*plistValue, error) {
+	v := &plistValue{kind: start.Name.Local}
+
+	switch v.kind {
+	case "dict":
+		for {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := tok.(type) {
+			case xml.EndElement:
+				return v, nil
+			case xml.StartElement:
+				if t.Name.Local != "key" {
+					return nil, fmt.Errorf("expected <key> in dict, got <%s>", t.Name.Local)
+				}
+				var key string
+				if err := dec.DecodeElement(&key, &t); err != nil {
+					return nil, err
+				}
+				valueStart, err := nextStart(dec)
+				if err != nil {
+					return nil, err
+				}
+				child, err := decodePlist(dec, valueStart)
+				if err != nil {
+					return nil, err
+				}
+				v.keys = append(v.keys, key)
+				v.values = append(v.values, child)
+			}
+		}
+	case "array":
+		for {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := tok.(type) {
+			case xml.EndElement:
+				return v, nil
+			case xml.StartElement:
+				child, err := decodePlist(dec, t)
+				if err != nil {
+					return nil, err
+				}
+				v.values = append(v.values, child)
+			}
+		}
+	default:
+		if err := dec.DecodeElement(&v.text, &start); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+}
+
+func nextStart(dec *xml.Decoder) (xml.StartElement, error) {
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return xml.StartElement{}, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			return t, nil
+		case xml.EndElement:
+			return xml.StartElement{}, fmt.Errorf("missing value for key")
+		}
+	}
+}
+
+// Write writes the property list in XML format.
+func (d *StringsDict) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	bw.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	bw.WriteString(`<plist version="1.0">` + "\n")
+	writePlist(bw, d.root, 0)
+	bw.WriteString("</plist>\n")
+	return bw.Flush()
+}
+
+func writePlist(w *bufio.Writer, v *plistValue, depth int) {
+	indent := strings.Repeat("\t", depth)
+
+	switch v.kind {
+	case "dict":
+		w.WriteString(indent + "<dict>\n")
+		for i, key := range v.keys {
+			w.WriteString(indent + "\t<key>")
+			xml.EscapeText(w, []byte(key))
+			w.WriteString("</key>\n")
+			writePlist(w, v.values[i], depth+1)
+		}
+		w.WriteString(indent + "</dict>\n")
+	case "array":
+		w.WriteString(indent + "<array>\n")
+		for _, child := range v.values {
+			writePlist(w, child, depth+1)
+		}
+		w.WriteString(indent + "</array>\n")
+	case "true", "false":
+		w.WriteString(indent + "<" + v.kind + "/>\n")
+	default:
+		w.WriteString(indent + "<" + v.kind + ">")
+		xml.EscapeText(w, []byte(v.text))
+		w.WriteString("</" + v.kind + ">\n")
+	}
+}
+
+// clone deep copies a property list value.
+func (v *plistValue) clone() *plistValue {
+	c := &plistValue{kind: v.kind, text: v.text, keys: append([]string(nil), v.keys...)}
+	for _, child := range v.values {
+		c.values = append(c.values, child.clone())
+	}
+	return c
+}
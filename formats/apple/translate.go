@@ -0,0 +1,145 @@
+package apple
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/internal/placeholder"
+	"github.com/AdolfZahid1/godeeplapi/internal/plural"
+	"github.com/AdolfZahid1/godeeplapi/internal/textbatch"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+// protected matches format specifiers and .stringsdict variables (%#@files@).
+var protected = regexp.MustCompile(`%#@[^@]+@|` + placeholder.Printf.String())
+
+// TranslateStrings returns a copy of source with all values translated into
+// req.TargetLang. The comment of an entry is sent as Context. req is used as a
+// template for every request.
+func TranslateStrings(ctx context.Context, t godeeplapi.Translator, source *StringsFile, req models.TranslationRequest) (*StringsFile, error) {
+	out := &StringsFile{}
+	var comments []string
+	groups := make(map[string][]*StringsEntry)
+
+	for _, e := range source.Entries {
+		entry := *e
+		out.Entries = append(out.Entries, &entry)
+		if strings.TrimSpace(entry.Value) == "" {
+			continue
+		}
+		if _, ok := groups[entry.Comment]; !ok {
+			comments = append(comments, entry.Comment)
+		}
+		groups[entry.Comment] = append(groups[entry.Comment], &entry)
+	}
+
+	for _, comment := range comments {
+		entries := groups[comment]
+		values := make([]*string, len(entries))
+		for i, e := range entries {
+			values[i] = &e.Value
+		}
+
+		r := req
+		r.Context = comment
+		if err := translateValues(ctx, t, r, values); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// TranslateStringsDict returns a copy of source translated into
+// req.TargetLang. Plural rules get one form per plural category of the target
+// language. req is used as a template for every request.
+func TranslateStringsDict(ctx context.Context, t godeeplapi.Translator, source *StringsDict, req models.TranslationRequest) (*StringsDict, error) {
+	out := &StringsDict{root: source.root.clone()}
+
+	var values []*string
+	for _, entry := range out.root.values {
+		if entry.kind != "dict" {
+			continue
+		}
+		for i, key := range entry.keys {
+			value := entry.values[i]
+			switch {
+			case key == keyFormat && value.kind == "string":
+				values = append(values, &value.text)
+			case value.kind == "dict" && value.get(keySpecType) != nil && value.get(keySpecType).text == pluralRuleSpecKey:
+				entry.values[i] = pluralRule(value, req.TargetLang.String())
+				for j, k := range entry.values[i].keys {
+					if plural.IsCategory(k) {
+						values = append(values, &entry.values[i].values[j].text)
+					}
+				}
+			}
+		}
+	}
+
+	if err := translateValues(ctx, t, req, values); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// pluralRule rebuilds a plural rule dict with the categories of the target
+// language, falling back to the "other" form.
+func pluralRule(rule *plistValue, lang string) *plistValue {
+	out := &plistValue{kind: "dict"}
+	forms := make(map[string]*plistValue)
+	for i, key := range rule.keys {
+		if plural.IsCategory(key) {
+			forms[key] = rule.values[i]
+			continue
+		}
+		out.set(key, rule.values[i])
+	}
+
+	var extra []string
+	if _, ok := forms["zero"]; ok {
+		extra = append(extra, "zero")
+	}
+	for _, category := range plural.Merge(plural.Categories(lang), extra) {
+		form, ok := forms[category]
+		if !ok {
+			form = forms["other"]
+		}
+		if form != nil {
+			out.set(category, form.clone())
+		}
+	}
+	return out
+}
+
+// translateValues translates the strings in place with placeholders protected.
+func translateValues(ctx context.Context, t godeeplapi.Translator, req models.TranslationRequest, values []*string) error {
+	var targets []*string
+	var texts []string
+	var protectedValues [][]string
+
+	for _, v := range values {
+		if strings.TrimSpace(*v) == "" {
+			continue
+		}
+		text, p := placeholder.Protect(*v, protected)
+		targets = append(targets, v)
+		texts = append(texts, text)
+		protectedValues = append(protectedValues, p)
+	}
+	if len(texts) == 0 {
+		return nil
+	}
+
+	req.TagHandling = models.TagXML
+	translated, err := textbatch.Translate(ctx, t, req, texts)
+	if err != nil {
+		return err
+	}
+	for i, v := range targets {
+		*v = placeholder.Restore(translated[i], protectedValues[i])
+	}
+	return nil
+}
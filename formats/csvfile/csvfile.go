@@ -0,0 +1,313 @@
+// Package csvfile translates selected columns of CSV and TSV files while
+// streaming them, so arbitrarily large exports can be processed.
+package csvfile
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/internal/textbatch"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+type options struct {
+	names      []string
+	indexes    []int
+	comma      rune
+	header     bool
+	startRow   int
+	checkpoint func(row int) error
+	columnName func(column, lang string) string
+}
+
+// Option configures Translate.
+type Option func(*options)
+
+// WithColumns selects the columns to translate by header name.
+func WithColumns(names ...string) Option {
+	return func(o *options) {
+		o.names = append(o.names, names...)
+	}
+}
+
+// WithColumnIndexes selects the columns to translate by zero-based index.
+func WithColumnIndexes(indexes ...int) Option {
+	return func(o *options) {
+		o.indexes = append(o.indexes, indexes...)
+	}
+}
+
+// WithComma sets the field delimiter, e.g. '\t' for TSV. Default ','.
+func WithComma(comma rune) Option {
+	return func(o *options) {
+		o.comma = comma
+	}
+}
+
+// WithoutHeader treats the first row as data. Columns can then only be
+// selected by index.
+func WithoutHeader() Option {
+	return func(o *options) {
+		o.header = false
+	}
+}
+
+// WithStartRow skips the first n data rows, e.g. the value returned by a
+// previous failed call. The header is not written again when n > 0, so the
+// output can be appended to the partial result.
+func WithStartRow(n int) Option {
+	return func(o *options) {
+		o.startRow = n
+	}
+}
+
+// WithCheckpoint registers a function that is called with the number of data
+// rows written after every flushed batch. Returning an error stops the run.
+func WithCheckpoint(fn func(row int) error) Option {
+	return func(o *options) {
+		o.checkpoint = fn
+	}
+}
+
+// WithColumnName sets how new column headers are named. The default is
+// "<column>_<lang>", e.g. "title_DE".
+func WithColumnName(fn func(column, lang string) string) Option {
+	return func(o *options) {
+		o.columnName = fn
+	}
+}
+
+// Translate reads CSV from r and writes it to w with one new column per
+// selected column and target language appended to every row. When targets is
+// empty req.TargetLang is used. Cells are batched across rows; empty cells
+// are not sent. It returns the number of data rows written; on failure that
+// is the row to resume from with WithStartRow.
+func Translate(ctx context.Context, t godeeplapi.Translator, r io.Reader, w io.Writer, req models.TranslationRequest, targets []models.Language, opts ...Option) (int, error) {
+	o := options{
+		comma:  ',',
+		header: true,
+		columnName: func(column, lang string) string {
+			return column + "_" + lang
+		},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if len(targets) == 0 {
+		targets = []models.Language{req.TargetLang}
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = o.comma
+	reader.FieldsPerRecord = -1
+	writer := csv.NewWriter(w)
+	writer.Comma = o.comma
+
+	var columns []int
+	width := 0
+
+	if o.header {
+		header, err := reader.Read()
+		if err != nil {
+			return 0, fmt.Errorf("csvfile: error reading header: %w", err)
+		}
+		if columns, err = resolveColumns(o, header); err != nil {
+			return 0, err
+		}
+		width = len(header)
+		if o.startRow == 0 {
+			out := append([]string(nil), header...)
+			for _, lang := range targets {
+				for _, c := range columns {
+					out = append(out, o.columnName(header[c], lang.String()))
+				}
+			}
+			if err := writer.Write(out); err != nil {
+				return 0, fmt.Errorf("csvfile: error writing header: %w", err)
+			}
+		}
+	} else {
+		if len(o.names) > 0 {
+			return 0, errors.New("csvfile: columns can only be selected by name when the file has a header")
+		}
+		columns = o.indexes
+		if err := checkColumns(columns, -1); err != nil {
+			return 0, err
+		}
+	}
+	if len(columns) == 0 {
+		return 0, errors.New("csvfile: no columns selected")
+	}
+
+	b := &batch{columns: columns, targets: targets, width: width}
+	written := o.startRow
+
+	flush := func() error {
+		if err := b.translate(ctx, t, req); err != nil {
+			return fmt.Errorf("csvfile: rows %d-%d: %w", written+1, written+len(b.rows), err)
+		}
+		for _, row := range b.rows {
+			if err := writer.Write(row); err != nil {
+				return fmt.Errorf("csvfile: error writing row %d: %w", written+1, err)
+			}
+			written++
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return fmt.Errorf("csvfile: error writing output: %w", err)
+		}
+		b.reset()
+		if o.checkpoint != nil {
+			return o.checkpoint(written)
+		}
+		return nil
+	}
+
+	row := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return written, fmt.Errorf("csvfile: error reading row %d: %w", row+1, err)
+		}
+		row++
+		if !o.header && row == 1 {
+			// Without a header the first row sets the number of columns
+			if err := checkColumns(columns, len(record)); err != nil {
+				return written, err
+			}
+		}
+		if row <= o.startRow {
+			continue
+		}
+
+		b.add(record)
+		if b.full() {
+			if err := flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+
+	if len(b.rows) > 0 {
+		if err := flush(); err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+func resolveColumns(o options, header []string) ([]int, error) {
+	columns := append([]int(nil), o.indexes...)
+	for _, name := range o.names {
+		found := false
+		for i, h := range header {
+			if h == name {
+				columns = append(columns, i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("csvfile: column %q not found in header", name)
+		}
+	}
+	if err := checkColumns(columns, len(header)); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+// checkColumns reports column indexes that are negative or, when width is
+// not negative, not below width.
+func checkColumns(columns []int, width int) error {
+	for _, c := range columns {
+		if c < 0 || (width >= 0 && c >= width) {
+			return fmt.Errorf("csvfile: column index %d out of range", c)
+		}
+	}
+	return nil
+}
+
+// batch collects rows until their distinct cells fill a request.
+type batch struct {
+	columns []int
+	targets []models.Language
+	// width is the header field count; shorter rows are padded to it so
+	// that the new columns line up.
+	width int
+
+	rows [][]string
+	// widths are the field counts of the rows after padding, before the
+	// translated columns are appended.
+	widths []int
+	texts  []string
+	index  map[string]int
+	size   int
+}
+
+func (b *batch) reset() {
+	b.rows = nil
+	b.widths = nil
+	b.texts = nil
+	b.index = nil
+	b.size = 0
+}
+
+func (b *batch) add(record []string) {
+	if b.index == nil {
+		b.index = make(map[string]int)
+	}
+	for len(record) < b.width {
+		record = append(record, "")
+	}
+	b.rows = append(b.rows, record)
+	b.widths = append(b.widths, len(record))
+	for _, c := range b.columns {
+		if c >= len(record) || record[c] == "" {
+			continue
+		}
+		if _, ok := b.index[record[c]]; !ok {
+			b.index[record[c]] = len(b.texts)
+			b.texts = append(b.texts, record[c])
+			b.size += len(record[c])
+		}
+	}
+}
+
+func (b *batch) full() bool {
+	return len(b.texts) >= textbatch.MaxTexts || b.size >= textbatch.MaxBytes
+}
+
+// translate appends the translated columns to every row of the batch.
+func (b *batch) translate(ctx context.Context, t godeeplapi.Translator, req models.TranslationRequest) error {
+	for _, lang := range b.targets {
+		var translated []string
+		if len(b.texts) > 0 {
+			r := req
+			r.TargetLang = lang
+			var err error
+			if translated, err = textbatch.Translate(ctx, t, r, b.texts); err != nil {
+				return err
+			}
+		}
+
+		for i, record := range b.rows {
+			for _, c := range b.columns {
+				cell := ""
+				if c < b.widths[i] && record[c] != "" {
+					cell = translated[b.index[record[c]]]
+				}
+				record = append(record, cell)
+			}
+			b.rows[i] = record
+		}
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package jsonlocale
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/internal/keypath"
+	"github.com/AdolfZahid1/godeeplapi/internal/placeholder"
+	"github.com/AdolfZahid1/godeeplapi/internal/plural"
+	"github.com/AdolfZahid1/godeeplapi/internal/textbatch"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+// DefaultPlaceholders matches i18next interpolation ({{name}}, {{- name}})
+// and nesting ($t(key)).
+var DefaultPlaceholders = regexp.MustCompile(`\{\{[^{}]*\}\}|\$t\([^()]*\)`)
+
+type options struct {
+	existing     *Value
+	previous     *Value
+	placeholders *regexp.Regexp
+}
+
+// Option configures Translate.
+type Option func(*options)
+
+// WithExisting passes the current target bundle. Keys already present in it
+// are reused instead of being translated again.
+func WithExisting(target *Value) Option {
+	return func(o *options) {
+		o.existing = target
+	}
+}
+
+// WithPreviousSource passes the source bundle the existing target was
+// translated from. Keys whose source text changed since then are translated
+// again even if the target has them.
+func WithPreviousSource(source *Value) Option {
+	return func(o *options) {
+		o.previous = source
+	}
+}
+
+// WithPlaceholders replaces the pattern of tokens that must survive
+// translation unchanged. Pass nil to disable placeholder protection.
+func WithPlaceholders(re *regexp.Regexp) Option {
+	return func(o *options) {
+		o.placeholders = re
+	}
+}
+
+// job is a string leaf of the output bundle that needs a translation.
+type job struct {
+	target *Value
+	text   string
+}
+
+type translator struct {
+	opts       options
+	targetLang string
+	jobs       []job
+}
+
+// Translate builds a bundle for req.TargetLang from source. Only string
+// leaves are translated; key order and all other values are kept. i18next
+// plural keys (key_one, key_other, ...) are expanded to the plural forms of
+// the target language. req is used as a template for every request.
+func Translate(ctx context.Context, t godeeplapi.Translator, source *Value, req models.TranslationRequest, opts ...Option) (*Value, error) {
+	tr := &translator{
+		opts:       options{placeholders: DefaultPlaceholders},
+		targetLang: req.TargetLang.String(),
+	}
+	for _, opt := range opts {
+		opt(&tr.opts)
+	}
+
+	out := tr.build(source, nil)
+	if len(tr.jobs) == 0 {
+		return out, nil
+	}
+
+	texts := make([]string, len(tr.jobs))
+	values := make([][]string, len(tr.jobs))
+	for i, j := range tr.jobs {
+		texts[i], values[i] = placeholder.Protect(j.text, tr.opts.placeholders)
+	}
+
+	req.TagHandling = models.TagXML
+	translated, err := textbatch.Translate(ctx, t, req, texts)
+	if err != nil {
+		return nil, err
+	}
+	for i, j := range tr.jobs {
+		j.target.Str = placeholder.Restore(translated[i], values[i])
+	}
+
+	return out, nil
+}
+
+// build copies v into a new tree, reusing or scheduling translations for string leaves.
+func (tr *translator) build(v *Value, path []string) *Value {
+	switch v.Kind {
+	case Object:
+		out := &Value{Kind: Object}
+		groups := pluralGroups(v)
+		emitted := make(map[string]bool)
+		for _, m := range v.Members {
+			if base, _, ok := splitPluralKey(m.Key); ok && groups[base] != nil {
+				if !emitted[base] {
+					emitted[base] = true
+					out.Members = append(out.Members, tr.buildPlural(base, groups[base], path)...)
+				}
+				continue
+			}
+			out.Members = append(out.Members, Member{Key: m.Key, Value: tr.build(m.Value, keypath.Append(path, m.Key))})
+		}
+		return out
+	case Array:
+		out := &Value{Kind: Array}
+		for i, item := range v.Items {
+			out.Items = append(out.Items, tr.build(item, keypath.Append(path, strconv.Itoa(i))))
+		}
+		return out
+	case String:
+		return tr.leaf(v.Str, path, path)
+	default:
+		return &Value{Kind: v.Kind, RawJSON: v.RawJSON}
+	}
+}
+
+// buildPlural emits one key per plural category of the target language.
+func (tr *translator) buildPlural(base string, forms map[string]string, path []string) []Member {
+	// i18next looks up _zero regardless of the language, so keep it if the source has one.
+	var extra []string
+	if _, ok := forms["zero"]; ok {
+		extra = append(extra, "zero")
+	}
+	categories := plural.Merge(plural.Categories(tr.targetLang), extra)
+
+	var members []Member
+	for _, category := range categories {
+		sourceCategory := category
+		if _, ok := forms[sourceCategory]; !ok {
+			sourceCategory = "other"
+		}
+		key := base + "_" + category
+		members = append(members, Member{
+			Key:   key,
+			Value: tr.leaf(forms[sourceCategory], keypath.Append(path, key), keypath.Append(path, base+"_"+sourceCategory)),
+		})
+	}
+	return members
+}
+
+// leaf reuses the existing translation at targetPath when the source text
+// at sourcePath has not changed, and schedules a translation otherwise.
+func (tr *translator) leaf(text string, targetPath, sourcePath []string) *Value {
+	out := &Value{Kind: String, Str: text}
+	if strings.TrimSpace(text) == "" {
+		return out
+	}
+
+	if existing := tr.opts.existing.Lookup(targetPath...); existing != nil && existing.Kind == String {
+		unchanged := true
+		if tr.opts.previous != nil {
+			prev := tr.opts.previous.Lookup(sourcePath...)
+			unchanged = prev != nil && prev.Kind == String && prev.Str == text
+		}
+		if unchanged {
+			out.Str = existing.Str
+			return out
+		}
+	}
+
+	tr.jobs = append(tr.jobs, job{target: out, text: text})
+	return out
+}
+
+// pluralGroups finds i18next plural groups among the string members of an
+// object. A group needs at least an _other form.
+func pluralGroups(v *Value) map[string]map[string]string {
+	groups := make(map[string]map[string]string)
+	for _, m := range v.Members {
+		base, category, ok := splitPluralKey(m.Key)
+		if !ok || m.Value.Kind != String {
+			continue
+		}
+		if groups[base] == nil {
+			groups[base] = make(map[string]string)
+		}
+		groups[base][category] = m.Value.Str
+	}
+	for base, forms := range groups {
+		if _, ok := forms["other"]; !ok {
+			delete(groups, base)
+		}
+	}
+	return groups
+}
+
+func splitPluralKey(key string) (base, category string, ok bool) {
+	i := strings.LastIndex(key, "_")
+	if i <= 0 || !plural.IsCategory(key[i+1:]) {
+		return "", "", false
+	}
+	return key[:i], key[i+1:], true
+}
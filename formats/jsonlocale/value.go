@@ -0,0 +1,218 @@
+// Package jsonlocale translates flat and nested JSON locale bundles such as
+// those used by i18next.
+package jsonlocale
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Kind is the JSON type of a Value.
+type Kind int
+
+const (
+	Object Kind = iota
+	Array
+	String
+	// Raw covers numbers, booleans and null, which are kept verbatim.
+	Raw
+)
+
+// Member is a key/value pair of an object.
+type Member struct {
+	Key   string
+	Value *Value
+}
+
+// Value is a JSON value that keeps the key order of objects.
+type Value struct {
+	Kind    Kind
+	Members []Member
+	Items   []*Value
+	Str     string
+	RawJSON json.RawMessage
+}
+
+// Get returns the member with the given key, or nil.
+func (v *Value) Get(key string) *Value {
+	if v == nil || v.Kind != Object {
+		return nil
+	}
+	for _, m := range v.Members {
+		if m.Key == key {
+			return m.Value
+		}
+	}
+	return nil
+}
+
+// Lookup follows a path of object keys and array indexes.
+func (v *Value) Lookup(path ...string) *Value {
+	for _, key := range path {
+		if v == nil {
+			return nil
+		}
+		switch v.Kind {
+		case Object:
+			v = v.Get(key)
+		case Array:
+			var i int
+			if _, err := fmt.Sscanf(key, "%d", &i); err != nil || i < 0 || i >= len(v.Items) {
+				return nil
+			}
+			v = v.Items[i]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+// Parse reads a JSON document.
+func Parse(r io.Reader) (*Value, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	v, err := parseValue(dec)
+	if err != nil {
+		return nil, fmt.Errorf("jsonlocale: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("jsonlocale: unexpected data after top-level value")
+	}
+	return v, nil
+}
+
+func parseValue(dec *json.Decoder) (*Value, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			v := &Value{Kind: Object}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, ok := keyTok.(string)
+				if !ok {
+					return nil, fmt.Errorf("expected object key, got %v", keyTok)
+				}
+				child, err := parseValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				v.Members = append(v.Members, Member{Key: key, Value: child})
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return v, nil
+		case '[':
+			v := &Value{Kind: Array}
+			for dec.More() {
+				child, err := parseValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				v.Items = append(v.Items, child)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return v, nil
+		}
+		return nil, fmt.Errorf("unexpected delimiter %v", t)
+	case string:
+		return &Value{Kind: String, Str: t}, nil
+	case json.Number:
+		return &Value{Kind: Raw, RawJSON: json.RawMessage(t.String())}, nil
+	case bool:
+		if t {
+			return &Value{Kind: Raw, RawJSON: json.RawMessage("true")}, nil
+		}
+		return &Value{Kind: Raw, RawJSON: json.RawMessage("false")}, nil
+	case nil:
+		return &Value{Kind: Raw, RawJSON: json.RawMessage("null")}, nil
+	}
+
+	return nil, fmt.Errorf("unexpected token %v", tok)
+}
+
+// Write writes the value as indented JSON followed by a newline.
+func (v *Value) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if err := writeValue(bw, v, 0); err != nil {
+		return err
+	}
+	bw.WriteString("\n")
+	return bw.Flush()
+}
+
+const indent = "  "
+
+func writeValue(w *bufio.Writer, v *Value, depth int) error {
+	switch v.Kind {
+	case Object:
+		if len(v.Members) == 0 {
+			w.WriteString("{}")
+			return nil
+		}
+		w.WriteString("{\n")
+		for i, m := range v.Members {
+			w.WriteString(strings.Repeat(indent, depth+1))
+			w.Write(encodeString(m.Key))
+			w.WriteString(": ")
+			if err := writeValue(w, m.Value, depth+1); err != nil {
+				return err
+			}
+			if i < len(v.Members)-1 {
+				w.WriteString(",")
+			}
+			w.WriteString("\n")
+		}
+		w.WriteString(strings.Repeat(indent, depth) + "}")
+	case Array:
+		if len(v.Items) == 0 {
+			w.WriteString("[]")
+			return nil
+		}
+		w.WriteString("[\n")
+		for i, item := range v.Items {
+			w.WriteString(strings.Repeat(indent, depth+1))
+			if err := writeValue(w, item, depth+1); err != nil {
+				return err
+			}
+			if i < len(v.Items)-1 {
+				w.WriteString(",")
+			}
+			w.WriteString("\n")
+		}
+		w.WriteString(strings.Repeat(indent, depth) + "]")
+	case String:
+		w.Write(encodeString(v.Str))
+	case Raw:
+		w.Write(v.RawJSON)
+	default:
+		return fmt.Errorf("jsonlocale: unknown value kind %d", v.Kind)
+	}
+	return nil
+}
+
+// encodeString encodes s as a JSON string without HTML escaping.
+func encodeString(s string) []byte {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return bytes.TrimRight(buf.Bytes(), "\n")
+}
@@ -0,0 +1,493 @@
+// Package po reads, translates and writes gettext PO and POT files.
+package po
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Entry is a single message of a PO file together with its comments.
+type Entry struct {
+	// Translator comments ("# ").
+	TranslatorComments []string
+	// Comments extracted from the source code ("#.").
+	ExtractedComments []string
+	// Source references ("#:").
+	References []string
+	// Flags such as fuzzy or c-format ("#,").
+	Flags []string
+	// Previous msgctxt/msgid lines of a fuzzy entry ("#|"), kept verbatim.
+	Previous []string
+
+	// Msgctxt disambiguates identical msgids. HasContext distinguishes an
+	// empty msgctxt from a missing one.
+	Msgctxt    string
+	HasContext bool
+
+	Msgid       string
+	MsgidPlural string
+	// Msgstr holds the translation. Plural entries have one element per plural form.
+	Msgstr []string
+
+	// Obsolete entries are written back prefixed with "#~".
+	Obsolete bool
+}
+
+// IsHeader reports whether the entry is the header of the file.
+func (e *Entry) IsHeader() bool {
+	return e.Msgid == "" && !e.HasContext && !e.Obsolete
+}
+
+// IsPlural reports whether the entry has plural forms.
+func (e *Entry) IsPlural() bool {
+	return e.MsgidPlural != ""
+}
+
+// IsFuzzy reports whether the entry is flagged as fuzzy.
+func (e *Entry) IsFuzzy() bool {
+	return e.HasFlag("fuzzy")
+}
+
+// HasFlag reports whether the entry carries the given flag.
+func (e *Entry) HasFlag(flag string) bool {
+	for _, f := range e.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// SetFlag adds or removes a flag.
+func (e *Entry) SetFlag(flag string, on bool) {
+	flags := e.Flags[:0:0]
+	for _, f := range e.Flags {
+		if f != flag {
+			flags = append(flags, f)
+		}
+	}
+	if on {
+		flags = append(flags, flag)
+	}
+	e.Flags = flags
+}
+
+// IsTranslated reports whether every msgstr of the entry is non-empty.
+func (e *Entry) IsTranslated() bool {
+	if len(e.Msgstr) == 0 {
+		return false
+	}
+	for _, s := range e.Msgstr {
+		if s == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// File is a parsed PO or POT file. Entries keep the order of the source file.
+type File struct {
+	Entries []*Entry
+}
+
+// Header returns the header entry, or nil if the file has none.
+func (f *File) Header() *Entry {
+	for _, e := range f.Entries {
+		if e.IsHeader() {
+			return e
+		}
+	}
+	return nil
+}
+
+// HeaderField returns the value of a header field such as "Language".
+func (f *File) HeaderField(name string) string {
+	h := f.Header()
+	if h == nil || len(h.Msgstr) == 0 {
+		return ""
+	}
+	for _, line := range strings.Split(h.Msgstr[0], "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(key), name) {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// SetHeaderField sets a header field, adding it if it does not exist yet.
+// A header entry is created when the file has none.
+func (f *File) SetHeaderField(name, value string) {
+	h := f.Header()
+	if h == nil {
+		h = &Entry{Msgstr: []string{""}}
+		f.Entries = append([]*Entry{h}, f.Entries...)
+	}
+	if len(h.Msgstr) == 0 {
+		h.Msgstr = []string{""}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(h.Msgstr[0], "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		lines = nil
+	}
+	found := false
+	for i, line := range lines {
+		key, _, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(key), name) {
+			lines[i] = name + ": " + value
+			found = true
+			break
+		}
+	}
+	if !found {
+		lines = append(lines, name+": "+value)
+	}
+	h.Msgstr[0] = strings.Join(lines, "\n") + "\n"
+}
+
+// NPlurals returns the number of plural forms declared in the Plural-Forms
+// header, or 2 when the header is missing or malformed.
+func (f *File) NPlurals() int {
+	for _, part := range strings.Split(f.HeaderField("Plural-Forms"), ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if ok && strings.TrimSpace(key) == "nplurals" {
+			if n, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && n > 0 {
+				return n
+			}
+		}
+	}
+	return 2
+}
+
+// Parse reads a PO or POT file.
+func Parse(r io.Reader) (*File, error) {
+	p := &parser{file: &File{}}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		p.line++
+		if err := p.parseLine(strings.TrimRight(scanner.Text(), "\r")); err != nil {
+			return nil, fmt.Errorf("po: line %d: %w", p.line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("po: error reading file: %w", err)
+	}
+	p.flush()
+
+	return p.file, nil
+}
+
+type parser struct {
+	file  *File
+	line  int
+	entry *Entry
+	// target points at the string that continuation lines are appended to.
+	target *string
+	// hasMsgstr is set once a msgstr was read, so that the next keyword
+	// or comment starts a new entry.
+	hasMsgstr bool
+}
+
+func (p *parser) current() *Entry {
+	if p.entry == nil {
+		p.entry = &Entry{}
+	}
+	return p.entry
+}
+
+func (p *parser) flush() {
+	if p.entry != nil {
+		p.file.Entries = append(p.file.Entries, p.entry)
+	}
+	p.entry = nil
+	p.target = nil
+	p.hasMsgstr = false
+}
+
+func (p *parser) parseLine(line string) error {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" {
+		p.flush()
+		return nil
+	}
+
+	obsolete := false
+	if strings.HasPrefix(trimmed, "#~") {
+		obsolete = true
+		trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, "#~"))
+		if strings.HasPrefix(trimmed, "|") {
+			p.startComment()
+			e := p.current()
+			e.Obsolete = true
+			e.Previous = append(e.Previous, strings.TrimSpace(trimmed[1:]))
+			return nil
+		}
+	}
+
+	if !obsolete && strings.HasPrefix(trimmed, "#") {
+		p.startComment()
+		return p.parseComment(trimmed)
+	}
+
+	if strings.HasPrefix(trimmed, "\"") {
+		if p.target == nil {
+			return fmt.Errorf("unexpected string continuation")
+		}
+		s, err := unquote(trimmed)
+		if err != nil {
+			return err
+		}
+		*p.target += s
+		return nil
+	}
+
+	keyword, rest, _ := strings.Cut(trimmed, " ")
+	value, err := unquote(strings.TrimSpace(rest))
+	if err != nil {
+		return err
+	}
+
+	if p.hasMsgstr && !strings.HasPrefix(keyword, "msgstr") {
+		p.flush()
+	}
+	e := p.current()
+	if obsolete {
+		e.Obsolete = true
+	}
+
+	switch {
+	case keyword == "msgctxt":
+		e.Msgctxt = value
+		e.HasContext = true
+		p.target = &e.Msgctxt
+	case keyword == "msgid":
+		e.Msgid = value
+		p.target = &e.Msgid
+	case keyword == "msgid_plural":
+		e.MsgidPlural = value
+		p.target = &e.MsgidPlural
+	case keyword == "msgstr":
+		e.Msgstr = append(e.Msgstr[:0], value)
+		p.target = &e.Msgstr[0]
+		p.hasMsgstr = true
+	case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]"):
+		index, err := strconv.Atoi(keyword[len("msgstr[") : len(keyword)-1])
+		if err != nil || index < 0 {
+			return fmt.Errorf("invalid plural index in %q", keyword)
+		}
+		for len(e.Msgstr) <= index {
+			e.Msgstr = append(e.Msgstr, "")
+		}
+		e.Msgstr[index] = value
+		p.target = &e.Msgstr[index]
+		p.hasMsgstr = true
+	default:
+		return fmt.Errorf("unknown keyword %q", keyword)
+	}
+
+	return nil
+}
+
+// startComment begins a new entry if the current one is already complete.
+func (p *parser) startComment() {
+	if p.hasMsgstr {
+		p.flush()
+	}
+	p.target = nil
+}
+
+func (p *parser) parseComment(line string) error {
+	e := p.current()
+	kind, text := line[:min(2, len(line))], ""
+	if len(line) > 2 {
+		text = strings.TrimPrefix(line[2:], " ")
+	}
+
+	switch kind {
+	case "#.":
+		e.ExtractedComments = append(e.ExtractedComments, text)
+	case "#:":
+		e.References = append(e.References, text)
+	case "#,":
+		for _, flag := range strings.Split(text, ",") {
+			if flag = strings.TrimSpace(flag); flag != "" {
+				e.Flags = append(e.Flags, flag)
+			}
+		}
+	case "#|":
+		e.Previous = append(e.Previous, text)
+	default:
+		e.TranslatorComments = append(e.TranslatorComments, strings.TrimPrefix(line[1:], " "))
+	}
+
+	return nil
+}
+
+// Write writes the file in PO format.
+func (f *File) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	for i, e := range f.Entries {
+		if i > 0 {
+			bw.WriteString("\n")
+		}
+		writeEntry(bw, e)
+	}
+
+	return bw.Flush()
+}
+
+func writeEntry(w *bufio.Writer, e *Entry) {
+	for _, c := range e.TranslatorComments {
+		writeComment(w, "#", c)
+	}
+	for _, c := range e.ExtractedComments {
+		writeComment(w, "#.", c)
+	}
+	for _, c := range e.References {
+		writeComment(w, "#:", c)
+	}
+	if len(e.Flags) > 0 {
+		writeComment(w, "#,", strings.Join(e.Flags, ", "))
+	}
+	for _, c := range e.Previous {
+		if e.Obsolete {
+			writeComment(w, "#~|", c)
+		} else {
+			writeComment(w, "#|", c)
+		}
+	}
+
+	prefix := ""
+	if e.Obsolete {
+		prefix = "#~ "
+	}
+	if e.HasContext {
+		writeString(w, prefix, "msgctxt", e.Msgctxt)
+	}
+	writeString(w, prefix, "msgid", e.Msgid)
+	if e.IsPlural() {
+		writeString(w, prefix, "msgid_plural", e.MsgidPlural)
+		msgstr := e.Msgstr
+		if len(msgstr) == 0 {
+			msgstr = []string{"", ""}
+		}
+		for i, s := range msgstr {
+			writeString(w, prefix, fmt.Sprintf("msgstr[%d]", i), s)
+		}
+		return
+	}
+
+	msgstr := ""
+	if len(e.Msgstr) > 0 {
+		msgstr = e.Msgstr[0]
+	}
+	writeString(w, prefix, "msgstr", msgstr)
+}
+
+func writeComment(w *bufio.Writer, kind, text string) {
+	w.WriteString(kind)
+	if text != "" {
+		w.WriteString(" ")
+		w.WriteString(text)
+	}
+	w.WriteString("\n")
+}
+
+// writeString writes a keyword and its value. Values with embedded newlines
+// are split into one quoted line per newline, as xgettext does.
+func writeString(w *bufio.Writer, prefix, keyword, value string) {
+	lines := splitLines(value)
+	if len(lines) <= 1 {
+		fmt.Fprintf(w, "%s%s %s\n", prefix, keyword, quote(value))
+		return
+	}
+
+	fmt.Fprintf(w, "%s%s \"\"\n", prefix, keyword)
+	for _, line := range lines {
+		fmt.Fprintf(w, "%s%s\n", prefix, quote(line))
+	}
+}
+
+func splitLines(s string) []string {
+	var lines []string
+	for {
+		i := strings.Index(s, "\n")
+		if i < 0 || i == len(s)-1 {
+			break
+		}
+		lines = append(lines, s[:i+1])
+		s = s[i+1:]
+	}
+	return append(lines, s)
+}
+
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func unquote(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("invalid quoted string %s", s)
+	}
+	s = s[1 : len(s)-1]
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		if i == len(s) {
+			return "", fmt.Errorf("trailing backslash in string")
+		}
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case 'a':
+			b.WriteByte('\a')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'v':
+			b.WriteByte('\v')
+		case '\\', '"', '\'', '?':
+			b.WriteByte(s[i])
+		default:
+			return "", fmt.Errorf("unknown escape sequence \\%c", s[i])
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,143 @@
+package po
+
+import (
+	"context"
+	"strings"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/internal/textbatch"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+type options struct {
+	includeFuzzy bool
+	markFuzzy    bool
+}
+
+// Option configures Translate.
+type Option func(*options)
+
+// WithFuzzy controls whether entries flagged as fuzzy are translated again.
+// Enabled by default.
+func WithFuzzy(include bool) Option {
+	return func(o *options) {
+		o.includeFuzzy = include
+	}
+}
+
+// WithMarkFuzzy flags machine translated entries as fuzzy so they show up
+// for review in PO editors. Disabled by default.
+func WithMarkFuzzy(mark bool) Option {
+	return func(o *options) {
+		o.markFuzzy = mark
+	}
+}
+
+// segment is one text sent to DeepL and the msgstr slot it fills.
+type segment struct {
+	entry *Entry
+	index int
+	text  string
+}
+
+// Translate fills in the untranslated (and, by default, fuzzy) entries of f.
+// req is used as a template for every request: set TargetLang and any other
+// options there; Text and Context are filled in per entry. The msgctxt and
+// comments of an entry are sent as Context. The header and obsolete entries
+// are never translated. It returns the number of translated entries.
+func Translate(ctx context.Context, t godeeplapi.Translator, f *File, req models.TranslationRequest, opts ...Option) (int, error) {
+	o := options{includeFuzzy: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	nplurals := f.NPlurals()
+
+	// Entries are grouped by context so that each request carries a single Context value.
+	var contexts []string
+	groups := make(map[string][]segment)
+	var pending []*Entry
+	forms := make(map[*Entry]int)
+
+	for _, e := range f.Entries {
+		if e.IsHeader() || e.Obsolete || e.Msgid == "" {
+			continue
+		}
+		if e.IsTranslated() && !(o.includeFuzzy && e.IsFuzzy()) {
+			continue
+		}
+
+		key := entryContext(e)
+		if _, ok := groups[key]; !ok {
+			contexts = append(contexts, key)
+		}
+
+		if e.IsPlural() {
+			n := len(e.Msgstr)
+			if n < 2 {
+				n = nplurals
+			}
+			forms[e] = n
+			groups[key] = append(groups[key], segment{entry: e, index: 0, text: e.Msgid})
+			for i := 1; i < n; i++ {
+				groups[key] = append(groups[key], segment{entry: e, index: i, text: e.MsgidPlural})
+			}
+		} else {
+			forms[e] = 1
+			groups[key] = append(groups[key], segment{entry: e, index: 0, text: e.Msgid})
+		}
+		pending = append(pending, e)
+	}
+
+	// f is only changed once every group is translated, so that an error
+	// leaves the existing translations intact.
+	results := make(map[string][]string, len(contexts))
+	for _, key := range contexts {
+		segments := groups[key]
+		texts := make([]string, len(segments))
+		for i, s := range segments {
+			texts[i] = s.text
+		}
+
+		r := req
+		r.Context = key
+		translated, err := textbatch.Translate(ctx, t, r, texts)
+		if err != nil {
+			return 0, err
+		}
+		results[key] = translated
+	}
+
+	for _, e := range pending {
+		e.Msgstr = make([]string, forms[e])
+	}
+	for _, key := range contexts {
+		for i, s := range groups[key] {
+			s.entry.Msgstr[s.index] = results[key][i]
+		}
+	}
+
+	for _, e := range pending {
+		e.SetFlag("fuzzy", o.markFuzzy)
+		if !o.markFuzzy {
+			e.Previous = nil
+		}
+	}
+
+	if len(pending) > 0 && req.TargetLang != "" {
+		f.SetHeaderField("Language", req.TargetLang.Locale("_"))
+	}
+
+	return len(pending), nil
+}
+
+// entryContext builds the Context value for an entry from its msgctxt and comments.
+func entryContext(e *Entry) string {
+	var parts []string
+	if e.HasContext && e.Msgctxt != "" {
+		parts = append(parts, e.Msgctxt)
+	}
+	parts = append(parts, e.ExtractedComments...)
+	parts = append(parts, e.TranslatorComments...)
+	return strings.Join(parts, "\n")
+}
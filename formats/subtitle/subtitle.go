@@ -0,0 +1,234 @@
+// Package subtitle reads, translates and writes SRT and WebVTT subtitles.
+package subtitle
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is a subtitle file format.
+type Format int
+
+const (
+	SRT Format = iota
+	WebVTT
+)
+
+// Cue is a single subtitle.
+type Cue struct {
+	// ID is the cue identifier: the sequence number in SRT, optional in WebVTT.
+	ID    string
+	Start time.Duration
+	End   time.Duration
+	// Settings are the WebVTT cue settings following the timing, e.g. "line:0".
+	Settings string
+	// Lines of text, including styling tags.
+	Lines []string
+	// Blocks are WebVTT NOTE, STYLE and REGION blocks preceding the cue, verbatim.
+	Blocks []string
+}
+
+// File is a parsed subtitle file.
+type File struct {
+	Format Format
+	// Header is the first block of a WebVTT file, starting with "WEBVTT".
+	Header string
+	Cues   []*Cue
+	// Trailer holds WebVTT blocks after the last cue.
+	Trailer []string
+}
+
+// Parse reads an SRT or WebVTT file. The format is detected from the WEBVTT signature.
+func Parse(r io.Reader) (*File, error) {
+	blocks, err := readBlocks(r)
+	if err != nil {
+		return nil, fmt.Errorf("subtitle: error reading file: %w", err)
+	}
+
+	f := &File{Format: SRT}
+	if len(blocks) > 0 && strings.HasPrefix(blocks[0][0], "WEBVTT") {
+		f.Format = WebVTT
+		f.Header = strings.Join(blocks[0], "\n")
+		blocks = blocks[1:]
+	}
+
+	var pending []string
+	for _, block := range blocks {
+		if f.Format == WebVTT && isVTTMetadata(block[0]) {
+			pending = append(pending, strings.Join(block, "\n"))
+			continue
+		}
+
+		cue, err := parseCue(block, f.Format)
+		if err != nil {
+			return nil, fmt.Errorf("subtitle: cue %d: %w", len(f.Cues)+1, err)
+		}
+		cue.Blocks = pending
+		pending = nil
+		f.Cues = append(f.Cues, cue)
+	}
+	f.Trailer = pending
+
+	return f, nil
+}
+
+// readBlocks splits the input into blocks of non-empty lines.
+func readBlocks(r io.Reader) ([][]string, error) {
+	var blocks [][]string
+	var current []string
+
+	scanner := bufio.NewScanner(r)
+	first := true
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if first {
+			line = strings.TrimPrefix(line, "\ufeff")
+			first = false
+		}
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				blocks = append(blocks, current)
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		blocks = append(blocks, current)
+	}
+
+	return blocks, scanner.Err()
+}
+
+func isVTTMetadata(line string) bool {
+	for _, prefix := range []string{"NOTE", "STYLE", "REGION"} {
+		if line == prefix || strings.HasPrefix(line, prefix+" ") || strings.HasPrefix(line, prefix+"\t") {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCue(block []string, format Format) (*Cue, error) {
+	cue := &Cue{}
+
+	timing := 0
+	if !strings.Contains(block[0], "-->") {
+		cue.ID = block[0]
+		timing = 1
+	}
+	if timing >= len(block) || !strings.Contains(block[timing], "-->") {
+		return nil, fmt.Errorf("missing timing line")
+	}
+
+	startText, rest, _ := strings.Cut(block[timing], "-->")
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("missing end time")
+	}
+
+	var err error
+	if cue.Start, err = parseTimestamp(strings.TrimSpace(startText)); err != nil {
+		return nil, err
+	}
+	if cue.End, err = parseTimestamp(fields[0]); err != nil {
+		return nil, err
+	}
+	if format == WebVTT {
+		cue.Settings = strings.Join(fields[1:], " ")
+	}
+
+	cue.Lines = append([]string(nil), block[timing+1:]...)
+	return cue, nil
+}
+
+// parseTimestamp parses hh:mm:ss,mmm (SRT) and [hh:]mm:ss.mmm (WebVTT).
+func parseTimestamp(s string) (time.Duration, error) {
+	main, frac, ok := strings.Cut(strings.Replace(s, ",", ".", 1), ".")
+	if !ok || len(frac) != 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+
+	parts := strings.Split(main, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+
+	var total time.Duration
+	units := []time.Duration{time.Second, time.Minute, time.Hour}
+	for i := range parts {
+		n, err := strconv.Atoi(parts[len(parts)-1-i])
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid timestamp %q", s)
+		}
+		total += time.Duration(n) * units[i]
+	}
+
+	ms, err := strconv.Atoi(frac)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	return total + time.Duration(ms)*time.Millisecond, nil
+}
+
+func formatTimestamp(d time.Duration, format Format) string {
+	h := d / time.Hour
+	m := d % time.Hour / time.Minute
+	s := d % time.Minute / time.Second
+	ms := d % time.Second / time.Millisecond
+
+	sep := ","
+	if format == WebVTT {
+		sep = "."
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", h, m, s, sep, ms)
+}
+
+// Write writes the file in its format.
+func (f *File) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	if f.Format == WebVTT {
+		header := f.Header
+		if header == "" {
+			header = "WEBVTT"
+		}
+		bw.WriteString(header + "\n\n")
+	}
+
+	for i, cue := range f.Cues {
+		for _, block := range cue.Blocks {
+			bw.WriteString(block + "\n\n")
+		}
+
+		id := cue.ID
+		if f.Format == SRT && id == "" {
+			id = strconv.Itoa(i + 1)
+		}
+		if id != "" {
+			bw.WriteString(id + "\n")
+		}
+
+		bw.WriteString(formatTimestamp(cue.Start, f.Format) + " --> " + formatTimestamp(cue.End, f.Format))
+		if f.Format == WebVTT && cue.Settings != "" {
+			bw.WriteString(" " + cue.Settings)
+		}
+		bw.WriteString("\n")
+
+		for _, line := range cue.Lines {
+			bw.WriteString(line + "\n")
+		}
+		bw.WriteString("\n")
+	}
+
+	for _, block := range f.Trailer {
+		bw.WriteString(block + "\n\n")
+	}
+
+	return bw.Flush()
+}
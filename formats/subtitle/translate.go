@@ -0,0 +1,231 @@
+package subtitle
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/internal/placeholder"
+	"github.com/AdolfZahid1/godeeplapi/internal/textbatch"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+// tags matches styling markup: HTML-like tags, WebVTT voice/class spans and
+// timestamps, and ASS override codes such as {\an8}.
+var tags = regexp.MustCompile(`<[^<>]*>|\{\\[^{}]*\}`)
+
+type options struct {
+	batchSize     int
+	contextCues   int
+	maxLineLength int
+}
+
+// Option configures Translate.
+type Option func(*options)
+
+// WithBatchSize sets how many consecutive cues share the same Context.
+// Batches are split into several requests when their texts exceed the
+// request limits. Default 20.
+func WithBatchSize(n int) Option {
+	return func(o *options) {
+		o.batchSize = n
+	}
+}
+
+// WithContextCues sets how many cues before and after a batch are sent as
+// Context. Default 2.
+func WithContextCues(n int) Option {
+	return func(o *options) {
+		o.contextCues = n
+	}
+}
+
+// WithMaxLineLength sets the maximum number of visible characters per line
+// when re-wrapping translated cues. Zero keeps the source line count
+// without a length limit. Default 42.
+func WithMaxLineLength(n int) Option {
+	return func(o *options) {
+		o.maxLineLength = n
+	}
+}
+
+// unit is a text sent for translation: a whole cue, or one line of a dialogue cue.
+type unit struct {
+	cue    int
+	line   int // -1 for the whole cue
+	text   string
+	values []string
+}
+
+// Translate returns a copy of f with the cue text translated into
+// req.TargetLang. Timing, identifiers, settings and styling tags are kept.
+// Cue lines are joined for translation and re-wrapped afterwards, except for
+// dialogue cues where every line starts with a dash. req is used as a
+// template for every request.
+func Translate(ctx context.Context, t godeeplapi.Translator, f *File, req models.TranslationRequest, opts ...Option) (*File, error) {
+	o := options{batchSize: 20, contextCues: 2, maxLineLength: 42}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.batchSize <= 0 || o.batchSize > 50 {
+		return nil, fmt.Errorf("subtitle: batch size must be between 1 and 50")
+	}
+
+	out := &File{Format: f.Format, Header: f.Header, Trailer: f.Trailer}
+	for _, cue := range f.Cues {
+		c := *cue
+		c.Lines = append([]string(nil), cue.Lines...)
+		out.Cues = append(out.Cues, &c)
+	}
+
+	req.TagHandling = models.TagXML
+
+	for start := 0; start < len(out.Cues); start += o.batchSize {
+		end := min(start+o.batchSize, len(out.Cues))
+
+		var units []unit
+		for i := start; i < end; i++ {
+			units = append(units, cueUnits(i, out.Cues[i])...)
+		}
+		if len(units) == 0 {
+			continue
+		}
+
+		texts := make([]string, len(units))
+		for i, u := range units {
+			texts[i] = u.text
+		}
+
+		// Dialogue cues add a unit per line, so the units of a batch may
+		// still exceed the request limits.
+		r := req
+		r.Context = neighbours(f.Cues, start, end, o.contextCues)
+		translated, err := textbatch.Translate(ctx, t, r, texts)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, u := range units {
+			text := placeholder.Restore(translated[i], u.values)
+			cue := out.Cues[u.cue]
+			if u.line >= 0 {
+				cue.Lines[u.line] = text
+			} else {
+				cue.Lines = wrap(text, len(cue.Lines), o.maxLineLength)
+			}
+		}
+	}
+
+	return out, nil
+}
+
+func cueUnits(index int, cue *Cue) []unit {
+	if len(cue.Lines) == 0 {
+		return nil
+	}
+
+	if len(cue.Lines) > 1 && isDialogue(cue.Lines) {
+		var units []unit
+		for i, line := range cue.Lines {
+			text, values := placeholder.Protect(line, tags)
+			units = append(units, unit{cue: index, line: i, text: text, values: values})
+		}
+		return units
+	}
+
+	joined := strings.Join(cue.Lines, " ")
+	if strings.TrimSpace(tags.ReplaceAllString(joined, "")) == "" {
+		return nil
+	}
+	text, values := placeholder.Protect(joined, tags)
+	return []unit{{cue: index, line: -1, text: text, values: values}}
+}
+
+func isDialogue(lines []string) bool {
+	for _, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(tags.ReplaceAllString(line, "")), "-") {
+			return false
+		}
+	}
+	return true
+}
+
+// neighbours returns the plain text of up to n cues before and after [start, end).
+func neighbours(cues []*Cue, start, end, n int) string {
+	var parts []string
+	for i := max(0, start-n); i < start; i++ {
+		parts = append(parts, plainText(cues[i]))
+	}
+	for i := end; i < min(len(cues), end+n); i++ {
+		parts = append(parts, plainText(cues[i]))
+	}
+	return strings.Join(parts, "\n")
+}
+
+func plainText(cue *Cue) string {
+	return tags.ReplaceAllString(strings.Join(cue.Lines, " "), "")
+}
+
+// wrap splits text into at least lines lines of roughly equal visible length,
+// adding lines until none is longer than maxLen (when maxLen > 0).
+func wrap(text string, lines, maxLen int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{text}
+	}
+	if lines < 1 {
+		lines = 1
+	}
+
+	for n := lines; n <= len(words); n++ {
+		result := balance(words, n)
+		if maxLen <= 0 || fits(result, maxLen) {
+			return result
+		}
+	}
+	return balance(words, len(words))
+}
+
+// balance distributes words over n lines, aiming at equal visible length.
+func balance(words []string, n int) []string {
+	total := 0
+	for _, w := range words {
+		total += visibleLen(w) + 1
+	}
+	target := total / n
+
+	var result []string
+	var current []string
+	length := 0
+	for i, w := range words {
+		current = append(current, w)
+		length += visibleLen(w) + 1
+		remainingWords := len(words) - i - 1
+		remainingLines := n - len(result) - 1
+		if remainingLines > 0 && remainingWords >= remainingLines && (length >= target || remainingWords == remainingLines) {
+			result = append(result, strings.Join(current, " "))
+			current = nil
+			length = 0
+		}
+	}
+	if len(current) > 0 {
+		result = append(result, strings.Join(current, " "))
+	}
+	return result
+}
+
+func fits(lines []string, maxLen int) bool {
+	for _, line := range lines {
+		if visibleLen(line) > maxLen {
+			return false
+		}
+	}
+	return true
+}
+
+func visibleLen(s string) int {
+	return utf8.RuneCountInString(tags.ReplaceAllString(s, ""))
+}
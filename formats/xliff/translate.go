@@ -0,0 +1,107 @@
+package xliff
+
+import (
+	"context"
+	"strings"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/internal/textbatch"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+// Target states set on machine translated segments by default.
+const (
+	StateNeedsReviewTranslation = "needs-review-translation"
+	StateTranslated             = "translated"
+)
+
+// Inline elements whose content is native code and must not be translated.
+var (
+	ignoreTags12 = []string{"ph", "bpt", "ept", "it"}
+	ignoreTags20 = []string{"cp"}
+)
+
+type options struct {
+	state     string
+	overwrite bool
+}
+
+// Option configures Translate.
+type Option func(*options)
+
+// WithState sets the state given to translated segments. The default is
+// needs-review-translation for XLIFF 1.2 and translated for XLIFF 2.0,
+// which has no review state.
+func WithState(state string) Option {
+	return func(o *options) {
+		o.state = state
+	}
+}
+
+// WithOverwrite translates segments that already have a non-empty target.
+func WithOverwrite(overwrite bool) Option {
+	return func(o *options) {
+		o.overwrite = overwrite
+	}
+}
+
+// Translate fills in the targets of all translatable segments that have none.
+// Sources are sent with XML tag handling, so inline elements come back in
+// place. When req has no languages set, the ones from the file are used.
+// It returns the number of translated segments.
+func Translate(ctx context.Context, t godeeplapi.Translator, f *File, req models.TranslationRequest, opts ...Option) (int, error) {
+	o := options{state: StateNeedsReviewTranslation}
+	if f.Version == Version20 {
+		o.state = StateTranslated
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var segments []*Segment
+	var texts []string
+	for _, s := range f.Segments {
+		if !s.Translatable || strings.TrimSpace(s.Source) == "" {
+			continue
+		}
+		if s.HasTarget && strings.TrimSpace(s.Target) != "" && !o.overwrite {
+			continue
+		}
+		segments = append(segments, s)
+		texts = append(texts, s.Source)
+	}
+	if len(segments) == 0 {
+		return 0, nil
+	}
+
+	if req.TargetLang == "" && f.TargetLang != "" {
+		if lang, err := models.ParseLanguage(f.TargetLang); err == nil {
+			req.TargetLang = lang
+		}
+	}
+	if req.SourceLang == "" && f.SourceLang != "" {
+		if lang, err := models.ParseLanguage(f.SourceLang); err == nil {
+			req.SourceLang = lang.SourceCode()
+		}
+	}
+	req.TagHandling = models.TagXML
+	if f.Version == Version20 {
+		req.IgnoreTags = append(req.IgnoreTags, ignoreTags20...)
+	} else {
+		req.IgnoreTags = append(req.IgnoreTags, ignoreTags12...)
+	}
+
+	translated, err := textbatch.Translate(ctx, t, req, texts)
+	if err != nil {
+		return 0, err
+	}
+	for i, s := range segments {
+		s.SetTarget(translated[i], o.state)
+	}
+
+	if f.TargetLang == "" {
+		f.SetTargetLang(req.TargetLang.Locale("-"))
+	}
+
+	return len(segments), nil
+}
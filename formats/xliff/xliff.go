@@ -0,0 +1,331 @@
+// Package xliff reads, translates and writes XLIFF 1.2 and 2.0 files.
+//
+// Files are edited in place: only the targets that were changed and the
+// attributes that carry the target language are rewritten, everything else
+// is written back byte for byte.
+package xliff
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Supported XLIFF versions.
+const (
+	Version12 = "1.2"
+	Version20 = "2.0"
+)
+
+// Segment is a translatable piece of text: a trans-unit in XLIFF 1.2 or a
+// segment of a unit in XLIFF 2.0. Source and Target hold inner XML, so inline
+// elements such as <x/>, <g>, <ph/> or <pc> are kept as markup.
+type Segment struct {
+	// ID of the trans-unit (1.2) or unit (2.0).
+	ID string
+	// SegmentID is the id of the segment within its unit (2.0 only).
+	SegmentID string
+	Source    string
+	Target    string
+	HasTarget bool
+	// State of the target (1.2) or segment (2.0).
+	State string
+	// Translatable is false for units marked translate="no".
+	Translatable bool
+
+	dirty bool
+	// Byte offsets into the original file.
+	targetStart, targetEnd int
+	insertAt               int
+	indent                 string
+	targetName             xml.Name
+	targetAttrs            []xml.Attr
+	// Segment start tag (2.0), where the state attribute lives.
+	stateTag *tag
+}
+
+// SetTarget replaces the target inner XML and its state. The segment is
+// rewritten on the next Write.
+func (s *Segment) SetTarget(inner, state string) {
+	s.Target = inner
+	s.HasTarget = true
+	if state != "" {
+		s.State = state
+	}
+	s.dirty = true
+}
+
+// tag is a start tag that may have to be rewritten.
+type tag struct {
+	start, end int
+	name       xml.Name
+	attrs      []xml.Attr
+	dirty      bool
+}
+
+func (t *tag) set(name, value string) {
+	for i, a := range t.attrs {
+		if xmlName(a.Name) == name {
+			if a.Value != value {
+				t.attrs[i].Value = value
+				t.dirty = true
+			}
+			return
+		}
+	}
+	space, local, ok := strings.Cut(name, ":")
+	if !ok {
+		space, local = "", name
+	}
+	t.attrs = append(t.attrs, xml.Attr{Name: xml.Name{Space: space, Local: local}, Value: value})
+	t.dirty = true
+}
+
+// File is a parsed XLIFF document.
+type File struct {
+	Version    string
+	SourceLang string
+	TargetLang string
+	Segments   []*Segment
+
+	data []byte
+	// langTags carry the target language: <file> elements in 1.2, <xliff> in 2.0.
+	langTags []*tag
+}
+
+// SetTargetLang sets the target language attribute of the document.
+func (f *File) SetTargetLang(lang string) {
+	f.TargetLang = lang
+	attr := "target-language"
+	if f.Version == Version20 {
+		attr = "trgLang"
+	}
+	for _, t := range f.langTags {
+		t.set(attr, lang)
+	}
+}
+
+// Parse reads an XLIFF 1.2 or 2.0 document.
+func Parse(r io.Reader) (*File, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("xliff: error reading file: %w", err)
+	}
+
+	f := &File{data: data}
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = true
+
+	var (
+		stack        []string
+		unitID       string
+		translatable = true
+		cur          *Segment
+		whitespace   string
+		sourceStart  int
+		targetInner  int
+	)
+
+	for {
+		start := int(dec.InputOffset())
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("xliff: %w", err)
+		}
+		end := int(dec.InputOffset())
+
+		parent := ""
+		if len(stack) > 0 {
+			parent = stack[len(stack)-1]
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Name.Local)
+
+			switch {
+			case t.Name.Local == "xliff":
+				f.Version = attr(t, "version")
+				if strings.HasPrefix(f.Version, "2.") {
+					f.Version = Version20
+					f.SourceLang = attr(t, "srcLang")
+					f.TargetLang = attr(t, "trgLang")
+					f.langTags = append(f.langTags, newTag(t, start, end))
+				} else if f.Version != Version12 {
+					return nil, fmt.Errorf("xliff: unsupported version %q", f.Version)
+				}
+			case t.Name.Local == "file" && f.Version == Version12:
+				if f.SourceLang == "" {
+					f.SourceLang = attr(t, "source-language")
+					f.TargetLang = attr(t, "target-language")
+				}
+				f.langTags = append(f.langTags, newTag(t, start, end))
+			case t.Name.Local == "trans-unit" && f.Version == Version12:
+				cur = &Segment{ID: attr(t, "id"), Translatable: attr(t, "translate") != "no", targetStart: -1}
+				f.Segments = append(f.Segments, cur)
+			case t.Name.Local == "unit" && f.Version == Version20:
+				unitID = attr(t, "id")
+				translatable = attr(t, "translate") != "no"
+			case t.Name.Local == "segment" && f.Version == Version20 && parent == "unit":
+				cur = &Segment{
+					ID:           unitID,
+					SegmentID:    attr(t, "id"),
+					State:        attr(t, "state"),
+					Translatable: translatable,
+					targetStart:  -1,
+					stateTag:     newTag(t, start, end),
+				}
+				f.Segments = append(f.Segments, cur)
+			case t.Name.Local == "source" && cur != nil && isSegmentParent(parent):
+				sourceStart = end
+				cur.indent = whitespace
+			case t.Name.Local == "target" && cur != nil && isSegmentParent(parent):
+				cur.HasTarget = true
+				cur.targetStart = start
+				cur.targetName = t.Name
+				cur.targetAttrs = t.Attr
+				if f.Version == Version12 {
+					cur.State = attr(t, "state")
+				}
+				targetInner = end
+			}
+			whitespace = ""
+
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+			grandparent := ""
+			if len(stack) > 0 {
+				grandparent = stack[len(stack)-1]
+			}
+
+			switch {
+			case t.Name.Local == "source" && cur != nil && isSegmentParent(grandparent):
+				cur.Source = string(data[sourceStart:start])
+				cur.insertAt = end
+			case t.Name.Local == "target" && cur != nil && isSegmentParent(grandparent):
+				cur.Target = string(data[targetInner:start])
+				cur.targetEnd = end
+			case t.Name.Local == "trans-unit", t.Name.Local == "segment":
+				cur = nil
+			}
+			whitespace = ""
+
+		case xml.CharData:
+			if strings.TrimSpace(string(t)) == "" {
+				whitespace = string(t)
+			} else {
+				whitespace = ""
+			}
+		}
+	}
+
+	if f.Version == "" {
+		return nil, fmt.Errorf("xliff: missing <xliff> root element")
+	}
+	return f, nil
+}
+
+func isSegmentParent(name string) bool {
+	return name == "trans-unit" || name == "segment"
+}
+
+func attr(t xml.StartElement, name string) string {
+	for _, a := range t.Attr {
+		if xmlName(a.Name) == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func newTag(t xml.StartElement, start, end int) *tag {
+	return &tag{start: start, end: end, name: t.Name, attrs: append([]xml.Attr(nil), t.Attr...)}
+}
+
+// edit replaces data[start:end] with text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+// Write writes the document with all changes applied.
+func (f *File) Write(w io.Writer) error {
+	var edits []edit
+
+	for _, t := range f.langTags {
+		if t.dirty {
+			edits = append(edits, edit{t.start, t.end, startTag(t.name, t.attrs)})
+		}
+	}
+
+	for _, s := range f.Segments {
+		if !s.dirty {
+			continue
+		}
+
+		if s.stateTag != nil && s.State != "" {
+			s.stateTag.set("state", s.State)
+			if s.stateTag.dirty {
+				edits = append(edits, edit{s.stateTag.start, s.stateTag.end, startTag(s.stateTag.name, s.stateTag.attrs)})
+			}
+		}
+
+		name := s.targetName
+		if name.Local == "" {
+			name.Local = "target"
+		}
+		attrs := s.targetAttrs
+		if s.stateTag == nil && s.State != "" {
+			t := &tag{attrs: append([]xml.Attr(nil), attrs...)}
+			t.set("state", s.State)
+			attrs = t.attrs
+		}
+		element := startTag(name, attrs) + s.Target + "</" + xmlName(name) + ">"
+
+		if s.targetStart >= 0 {
+			edits = append(edits, edit{s.targetStart, s.targetEnd, element})
+		} else {
+			edits = append(edits, edit{s.insertAt, s.insertAt, s.indent + element})
+		}
+	}
+
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+
+	var buf bytes.Buffer
+	last := 0
+	for _, e := range edits {
+		buf.Write(f.data[last:e.start])
+		buf.WriteString(e.text)
+		last = e.end
+	}
+	buf.Write(f.data[last:])
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func startTag(name xml.Name, attrs []xml.Attr) string {
+	var b strings.Builder
+	b.WriteString("<" + xmlName(name))
+	for _, a := range attrs {
+		b.WriteString(" " + xmlName(a.Name) + `="`)
+		xml.EscapeText(&b, []byte(a.Value))
+		b.WriteString(`"`)
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+// xmlName formats a raw token name with its prefix.
+func xmlName(n xml.Name) string {
+	if n.Space == "" {
+		return n.Local
+	}
+	return n.Space + ":" + n.Local
+}
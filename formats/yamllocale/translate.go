@@ -0,0 +1,139 @@
+package yamllocale
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/internal/keypath"
+	"github.com/AdolfZahid1/godeeplapi/internal/placeholder"
+	"github.com/AdolfZahid1/godeeplapi/internal/textbatch"
+	"github.com/AdolfZahid1/godeeplapi/models"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPlaceholders matches Rails (%{name}), Symfony (%name%) and ICU ({name})
+// interpolation.
+var DefaultPlaceholders = regexp.MustCompile(`%\{[^{}]*\}|%[A-Za-z_][\w.-]*%|\{[^{}]*\}`)
+
+type options struct {
+	existing     *Document
+	previous     *Document
+	rootKey      string
+	placeholders *regexp.Regexp
+}
+
+// Option configures Translate.
+type Option func(*options)
+
+// WithExisting passes the current target file. Values already present in it
+// are reused instead of being translated again.
+func WithExisting(target *Document) Option {
+	return func(o *options) {
+		o.existing = target
+	}
+}
+
+// WithPreviousSource passes the source file the existing target was
+// translated from. Values whose source text changed since then are
+// translated again even if the target has them.
+func WithPreviousSource(source *Document) Option {
+	return func(o *options) {
+		o.previous = source
+	}
+}
+
+// WithRootKey sets the root key of the output file. By default it is derived
+// from the target language, e.g. PT-BR becomes pt-BR.
+func WithRootKey(key string) Option {
+	return func(o *options) {
+		o.rootKey = key
+	}
+}
+
+// WithPlaceholders replaces the pattern of tokens that must survive
+// translation unchanged. Pass nil to disable placeholder protection.
+func WithPlaceholders(re *regexp.Regexp) Option {
+	return func(o *options) {
+		o.placeholders = re
+	}
+}
+
+// Translate builds a locale file for req.TargetLang from source. The root key
+// is replaced by the target locale and only string scalars are translated.
+// Aliases are not translated themselves; they follow their anchor. req is
+// used as a template for every request.
+func Translate(ctx context.Context, t godeeplapi.Translator, source *Document, req models.TranslationRequest, opts ...Option) (*Document, error) {
+	o := options{placeholders: DefaultPlaceholders}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.rootKey == "" {
+		o.rootKey = req.TargetLang.Locale("-")
+	}
+
+	out := &Document{node: clone(source.node)}
+	rootKey, root := out.rootPair()
+	if rootKey == nil {
+		return out, nil
+	}
+	rootKey.Value = o.rootKey
+
+	var jobs []*yaml.Node
+	var walk func(n *yaml.Node, path []string)
+	walk = func(n *yaml.Node, path []string) {
+		switch n.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				walk(n.Content[i+1], keypath.Append(path, n.Content[i].Value))
+			}
+		case yaml.SequenceNode:
+			for i, child := range n.Content {
+				walk(child, keypath.Append(path, strconv.Itoa(i)))
+			}
+		case yaml.ScalarNode:
+			if !isText(n) || strings.TrimSpace(n.Value) == "" {
+				return
+			}
+			if existing, ok := o.existing.Lookup(path...); ok && o.unchanged(n.Value, path) {
+				n.Value = existing
+				return
+			}
+			jobs = append(jobs, n)
+		}
+	}
+	walk(root, nil)
+
+	if len(jobs) == 0 {
+		return out, nil
+	}
+
+	texts := make([]string, len(jobs))
+	values := make([][]string, len(jobs))
+	for i, n := range jobs {
+		texts[i], values[i] = placeholder.Protect(n.Value, o.placeholders)
+	}
+
+	req.TagHandling = models.TagXML
+	translated, err := textbatch.Translate(ctx, t, req, texts)
+	if err != nil {
+		return nil, err
+	}
+	for i, n := range jobs {
+		n.Value = placeholder.Restore(translated[i], values[i])
+	}
+
+	return out, nil
+}
+
+// unchanged reports whether the source text at path is the same as in the
+// previous source. Without a previous source every existing value is reused.
+func (o *options) unchanged(text string, path []string) bool {
+	if o.previous == nil {
+		return true
+	}
+	prev, ok := o.previous.Lookup(path...)
+	return ok && prev == text
+}
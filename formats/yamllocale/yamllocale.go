@@ -0,0 +1,143 @@
+// Package yamllocale translates YAML locale files rooted at a language key,
+// as used by Rails and Symfony:
+//
+//	en:
+//	  greeting: "Hello, %{name}"
+package yamllocale
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is a parsed locale file. Comments, anchors and key order are kept.
+type Document struct {
+	node *yaml.Node
+}
+
+// Parse reads a YAML locale file.
+func Parse(r io.Reader) (*Document, error) {
+	var node yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&node); err != nil {
+		return nil, fmt.Errorf("yamllocale: error decoding file: %w", err)
+	}
+	if node.Kind != yaml.DocumentNode || len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("yamllocale: top level value must be a mapping")
+	}
+	return &Document{node: &node}, nil
+}
+
+// Write writes the document as YAML with two space indentation.
+func (d *Document) Write(w io.Writer) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(d.node); err != nil {
+		return fmt.Errorf("yamllocale: error encoding file: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("yamllocale: error encoding file: %w", err)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Root returns the language key the file is rooted at, e.g. "en".
+func (d *Document) Root() string {
+	if key, _ := d.rootPair(); key != nil {
+		return key.Value
+	}
+	return ""
+}
+
+// Lookup returns the string value at the given path below the root key.
+func (d *Document) Lookup(path ...string) (string, bool) {
+	_, n := d.rootPair()
+	n = lookup(n, path)
+	if n == nil || !isText(n) {
+		return "", false
+	}
+	return n.Value, true
+}
+
+// rootPair returns the key and value nodes of the first top-level entry.
+func (d *Document) rootPair() (*yaml.Node, *yaml.Node) {
+	if d == nil || d.node == nil {
+		return nil, nil
+	}
+	top := d.node.Content[0]
+	if len(top.Content) < 2 {
+		return nil, nil
+	}
+	return top.Content[0], top.Content[1]
+}
+
+func lookup(n *yaml.Node, path []string) *yaml.Node {
+	for _, key := range path {
+		if n == nil {
+			return nil
+		}
+		if n.Kind == yaml.AliasNode {
+			n = n.Alias
+		}
+		switch n.Kind {
+		case yaml.MappingNode:
+			var next *yaml.Node
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if n.Content[i].Value == key {
+					next = n.Content[i+1]
+					break
+				}
+			}
+			n = next
+		case yaml.SequenceNode:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(n.Content) {
+				return nil
+			}
+			n = n.Content[i]
+		default:
+			return nil
+		}
+	}
+	return n
+}
+
+// isText reports whether n is a string scalar. Numbers, booleans, null and
+// other tagged scalars are left alone.
+func isText(n *yaml.Node) bool {
+	return n.Kind == yaml.ScalarNode && n.ShortTag() == "!!str"
+}
+
+// clone deep copies a node tree, keeping aliases pointed at the copied anchors.
+func clone(n *yaml.Node) *yaml.Node {
+	copies := make(map[*yaml.Node]*yaml.Node)
+	var walk func(*yaml.Node) *yaml.Node
+	walk = func(n *yaml.Node) *yaml.Node {
+		if n == nil {
+			return nil
+		}
+		if c, ok := copies[n]; ok {
+			return c
+		}
+		c := *n
+		copies[n] = &c
+		c.Content = make([]*yaml.Node, len(n.Content))
+		for i, child := range n.Content {
+			c.Content[i] = walk(child)
+		}
+		return &c
+	}
+	out := walk(n)
+
+	for _, c := range copies {
+		if c.Kind == yaml.AliasNode && c.Alias != nil {
+			c.Alias = walk(c.Alias)
+		}
+	}
+	return out
+}
@@ -31,6 +31,9 @@ func (c *Client) CreateGlossary(ctx context.Context, req models.CreateGlossaryRe
 	if err := c.checkAuth(); err != nil {
 		return nil, err
 	}
+	if err := req.ValidateWith(c.languageSupport(ctx)); err != nil {
+		return nil, err
+	}
 
 	respBody, err := c.doRequest(ctx, "POST", "/glossaries", req, nil)
 	if err != nil {
@@ -94,6 +97,9 @@ func (c *Client) EditGlossary(ctx context.Context, id string, req models.EditGlo
 	if err := c.checkAuth(); err != nil {
 		return nil, err
 	}
+	if err := req.ValidateWith(c.languageSupport(ctx)); err != nil {
+		return nil, err
+	}
 
 	respBody, err := c.doRequest(ctx, "PATCH", "/glossaries/"+id, req, nil)
 	if err != nil {
@@ -126,6 +132,9 @@ func (c *Client) DeleteAllLangDictionaries(ctx context.Context, id string, query
 	if err := c.checkAuth(); err != nil {
 		return err
 	}
+	if err := query.Validate(); err != nil {
+		return err
+	}
 
 	_, err := c.doRequestWithQuery(ctx, "DELETE", "/glossaries/"+id+"/dictionaries", nil, nil, query)
 	return err
@@ -137,6 +146,9 @@ func (c *Client) GetGlossaryEntries(ctx context.Context, id string, query models
 	if err := c.checkAuth(); err != nil {
 		return nil, err
 	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
 
 	respBody, err := c.doRequestWithQuery(ctx, "GET", "/glossaries/"+id+"/entries", nil, nil, query)
 	if err != nil {
@@ -158,6 +170,9 @@ func (c *Client) ReplaceOrCreateDictionaryInGlossary(ctx context.Context, id str
 	if err := c.checkAuth(); err != nil {
 		return nil, err
 	}
+	if err := req.ValidateWith(c.languageSupport(ctx)); err != nil {
+		return nil, err
+	}
 
 	respBody, err := c.doRequestWithQuery(ctx, "PUT", "/glossaries/"+id+"/dictionaries", req, nil, nil)
 	if err != nil {
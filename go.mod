@@ -2,3 +2,7 @@ module github.com/AdolfZahid1/godeeplapi
 
 go 1.24
 
+require (
+	golang.org/x/text v0.28.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -36,6 +36,24 @@ func (c *Client) doRequest(ctx context.Context, method, endpoint string, body in
 }
 
 func (c *Client) doRequestWithQuery(ctx context.Context, method, endpoint string, body interface{}, headers map[string]string, queryParams interface{}) ([]byte, error) {
+	if c == nil {
+		return nil, errors.New("client is nil")
+	}
+	if c.maxRetries <= 0 {
+		return c.sendRequest(ctx, method, endpoint, body, headers, queryParams)
+	}
+
+	result, err := c.retryRequest(ctx, func() (interface{}, error) {
+		return c.sendRequest(ctx, method, endpoint, body, headers, queryParams)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]byte), nil
+}
+
+// sendRequest sends a single request with a JSON body
+func (c *Client) sendRequest(ctx context.Context, method, endpoint string, body interface{}, headers map[string]string, queryParams interface{}) ([]byte, error) {
 	var bodyReader io.Reader
 	if c == nil {
 		return nil, errors.New("client is nil")
@@ -12,6 +12,9 @@ func (c *Client) ImproveText(ctx context.Context, req models.RephraseRequest) (s
 	if c.authKey == "" {
 		return "", fmt.Errorf("DeepL API token is empty")
 	}
+	if err := req.Validate(); err != nil {
+		return "", err
+	}
 
 	endpoint := "/write/rephrase"
 
@@ -25,10 +28,10 @@ func (c *Client) ImproveText(ctx context.Context, req models.RephraseRequest) (s
 		return "", fmt.Errorf("error unmarshaling response: %w", err)
 	}
 
-	if response.Text == "" {
+	if len(response.Improvements) == 0 || response.Improvements[0].Text == "" {
 		return "", fmt.Errorf("no improved text in response")
 	}
 
 	c.logger.Info("Successfully improved text")
-	return response.Text, nil
+	return response.Improvements[0].Text, nil
 }
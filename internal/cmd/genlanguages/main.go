@@ -0,0 +1,111 @@
+// Command genlanguages regenerates models/languages.go from a JSON snapshot
+// of the /languages responses. It is run by go generate in the models
+// package:
+//
+//	go generate ./models
+//
+// With -update the snapshot is first refreshed from the API using the key in
+// DEEPL_API_TOKEN. The write languages are kept, since the text improvement
+// API has no languages endpoint.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/AdolfZahid1/godeeplapi/internal/langgen"
+)
+
+func main() {
+	snapshot := flag.String("snapshot", "languages.json", "JSON snapshot of the /languages responses")
+	out := flag.String("out", "languages.go", "generated Go file")
+	update := flag.Bool("update", false, "refresh the snapshot from the API first")
+	pro := flag.Bool("pro", false, "use the pro API endpoint with -update")
+	flag.Parse()
+
+	if *update {
+		if err := updateSnapshot(*snapshot, *pro); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(*snapshot)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := langgen.Generate(data, filepath.Base(*snapshot))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func updateSnapshot(path string, pro bool) error {
+	key := os.Getenv("DEEPL_API_TOKEN")
+	if key == "" {
+		return errors.New("DEEPL_API_TOKEN is not set")
+	}
+
+	var s langgen.Snapshot
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("error decoding %s: %w", path, err)
+		}
+	}
+
+	baseURL := "https://api-free.deepl.com/v2"
+	if pro {
+		baseURL = "https://api.deepl.com/v2"
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var err error
+	if s.Source, err = fetch(ctx, baseURL, key, "source"); err != nil {
+		return err
+	}
+	if s.Target, err = fetch(ctx, baseURL, key, "target"); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// fetch requests a language list directly, so that the generator does not
+// depend on the package it generates.
+func fetch(ctx context.Context, baseURL, key, langType string) ([]langgen.Language, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/languages?type="+langType, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+key)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %s languages: %w", langType, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching %s languages: %s", langType, resp.Status)
+	}
+
+	var languages []langgen.Language
+	if err := json.NewDecoder(resp.Body).Decode(&languages); err != nil {
+		return nil, fmt.Errorf("error decoding %s languages: %w", langType, err)
+	}
+	return languages, nil
+}
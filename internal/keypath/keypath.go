@@ -0,0 +1,11 @@
+// Package keypath builds the key paths of nested locale files.
+package keypath
+
+// Append returns a copy of path with key added, so that sibling paths never
+// share a backing array.
+func Append(path []string, key string) []string {
+	out := make([]string, len(path)+1)
+	copy(out, path)
+	out[len(path)] = key
+	return out
+}
@@ -0,0 +1,193 @@
+// Package langgen generates models/languages.go from a snapshot of the
+// /languages responses, so that the language constants, the formality table
+// and the text improvement languages stay in sync with the API.
+package langgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// Language is an entry of a /languages response.
+type Language struct {
+	Language          string `json:"language"`
+	Name              string `json:"name"`
+	SupportsFormality bool   `json:"supports_formality,omitempty"`
+}
+
+// Snapshot holds saved /languages responses. Write lists the languages of the
+// text improvement API, which has no languages endpoint and is kept by hand.
+type Snapshot struct {
+	Source []Language `json:"source"`
+	Target []Language `json:"target"`
+	Write  []Language `json:"write"`
+}
+
+// fieldNames overrides the field name derived from the language name, for
+// variants and for names that existing code depends on.
+var fieldNames = map[string]string{
+	"EN-GB":   "EnglishGB",
+	"EN-US":   "EnglishUS",
+	"ES-419":  "SpanishLatAm",
+	"PT-BR":   "PortugueseBR",
+	"PT-PT":   "Portuguese",
+	"ZH":      "ChineseSimpl",
+	"ZH-HANS": "ChineseHans",
+	"ZH-HANT": "ChineseHant",
+}
+
+// FieldName returns the Go field name for a language: an override for the
+// code, or else the ASCII letters of the first word of its name.
+func FieldName(l Language) string {
+	if name, ok := fieldNames[strings.ToUpper(l.Language)]; ok {
+		return name
+	}
+	word, _, _ := strings.Cut(l.Name, " ")
+	var b strings.Builder
+	for _, r := range word {
+		if r < unicode.MaxASCII && unicode.IsLetter(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+type field struct {
+	Name string
+	Code string
+}
+
+// Generate returns the formatted source of models/languages.go for the JSON
+// snapshot. source names the snapshot file in the generated header.
+func Generate(snapshot []byte, source string) ([]byte, error) {
+	var s Snapshot
+	if err := json.Unmarshal(snapshot, &s); err != nil {
+		return nil, fmt.Errorf("langgen: error decoding snapshot: %w", err)
+	}
+	if len(s.Source) == 0 || len(s.Target) == 0 {
+		return nil, fmt.Errorf("langgen: snapshot has no source or target languages")
+	}
+
+	data := struct {
+		Source    string
+		Targets   []field
+		Sources   []field
+		Write     []field
+		Formality []string
+		Variants  []string
+	}{Source: source}
+
+	var err error
+	if data.Targets, err = fields("target", s.Target); err != nil {
+		return nil, err
+	}
+	if data.Sources, err = fields("source", s.Source); err != nil {
+		return nil, err
+	}
+	if data.Write, err = fields("write", s.Write); err != nil {
+		return nil, err
+	}
+	for _, l := range s.Target {
+		code := strings.ToUpper(l.Language)
+		if l.SupportsFormality {
+			data.Formality = append(data.Formality, code)
+		}
+		if strings.Contains(code, "-") {
+			data.Variants = append(data.Variants, code)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("langgen: %w", err)
+	}
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("langgen: error formatting output: %w", err)
+	}
+	return out, nil
+}
+
+func fields(list string, languages []Language) ([]field, error) {
+	seen := make(map[string]string)
+	out := make([]field, 0, len(languages))
+	for _, l := range languages {
+		name := FieldName(l)
+		if name == "" {
+			return nil, fmt.Errorf("langgen: %s language %q has no usable name", list, l.Language)
+		}
+		if code, ok := seen[name]; ok {
+			return nil, fmt.Errorf("langgen: %s languages %q and %q both map to field %s", list, code, l.Language, name)
+		}
+		seen[name] = l.Language
+		out = append(out, field{Name: name, Code: l.Language})
+	}
+	return out, nil
+}
+
+var tmpl = template.Must(template.New("languages").Parse(`// Code generated by genlanguages from {{.Source}}. DO NOT EDIT.
+
+package models
+
+// targetLanguageCode defines all supported target languages for translation.
+type targetLanguageCode struct {
+{{- range .Targets}}
+	{{.Name}} Language
+{{- end}}
+}
+
+// TargetLanguage is a predefined instance of targetLanguageCode.
+var TargetLanguage = targetLanguageCode{
+{{- range .Targets}}
+	{{.Name}}: {{printf "%q" .Code}},
+{{- end}}
+}
+
+// sourceLanguageCode defines all supported source languages for translation.
+type sourceLanguageCode struct {
+{{- range .Sources}}
+	{{.Name}} Language
+{{- end}}
+}
+
+// SourceLanguage is a predefined instance of sourceLanguageCode.
+var SourceLanguage = sourceLanguageCode{
+{{- range .Sources}}
+	{{.Name}}: {{printf "%q" .Code}},
+{{- end}}
+}
+
+// targetVariants are the target language variants supported by DeepL.
+var targetVariants = map[Language]bool{
+{{- range .Variants}}
+	{{printf "%q" .}}: true,
+{{- end}}
+}
+
+// formalityLanguages are the target languages that support the formality
+// parameter.
+var formalityLanguages = map[Language]bool{
+{{- range .Formality}}
+	{{printf "%q" .}}: true,
+{{- end}}
+}
+
+// ImproveTextLanguage defines language codes for the improve text API.
+type ImproveTextLanguage struct {
+{{- range .Write}}
+	{{.Name}} Language
+{{- end}}
+}
+
+// ImproveTextLanguages provides language codes for text improvement.
+var ImproveTextLanguages = ImproveTextLanguage{
+{{- range .Write}}
+	{{.Name}}: {{printf "%q" .Code}},
+{{- end}}
+}
+`))
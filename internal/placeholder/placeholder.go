@@ -0,0 +1,96 @@
+// Package placeholder shields placeholders such as {{name}} or %1$s from
+// translation by turning them into empty XML elements, which DeepL leaves
+// untouched when tag_handling is set to xml.
+package placeholder
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Printf matches printf style format specifiers as used by Android (%1$s, %d)
+// and Apple (%@, %1$@, %lld) resources, and literal %%.
+var Printf = regexp.MustCompile(`%(\d+\$)?[-#+ 0,(']*(\d+|\*)?(\.\d+)?(hh|h|ll|l|q|z|t|j)?[a-zA-Z@%]`)
+
+var (
+	escaper   = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	restoreRe = regexp.MustCompile(`<x id="(\d+)"\s*/>|<x id="(\d+)"></x>|&(amp|lt|gt|quot|apos);`)
+	entities  = map[string]string{"amp": "&", "lt": "<", "gt": ">", "quot": `"`, "apos": "'"}
+)
+
+// Protect replaces every match of re in text with an <x id="N"/> element and
+// XML-escapes the rest. It returns the protected text and the original
+// placeholders, indexed by N. A nil re only escapes the text.
+func Protect(text string, re *regexp.Regexp) (string, []string) {
+	if re == nil {
+		return escaper.Replace(text), nil
+	}
+
+	var b strings.Builder
+	var values []string
+	last := 0
+	for _, loc := range re.FindAllStringIndex(text, -1) {
+		b.WriteString(escaper.Replace(text[last:loc[0]]))
+		b.WriteString(`<x id="` + strconv.Itoa(len(values)) + `"/>`)
+		values = append(values, text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(escaper.Replace(text[last:]))
+
+	return b.String(), values
+}
+
+// Restore reverses Protect on a translated text.
+func Restore(text string, values []string) string {
+	return restore(text, values, true)
+}
+
+// ProtectMarkup is like Protect for text that already is XML: matches are
+// replaced with <x id="N"/> elements and nothing else is escaped.
+func ProtectMarkup(markup string, re *regexp.Regexp) (string, []string) {
+	if re == nil {
+		return markup, nil
+	}
+
+	var values []string
+	protected := re.ReplaceAllStringFunc(markup, func(m string) string {
+		values = append(values, m)
+		return `<x id="` + strconv.Itoa(len(values)-1) + `"/>`
+	})
+	return protected, values
+}
+
+// RestoreMarkup reverses ProtectMarkup, leaving entities untouched.
+func RestoreMarkup(markup string, values []string) string {
+	return restore(markup, values, false)
+}
+
+func restore(text string, values []string, unescape bool) string {
+	return restoreRe.ReplaceAllStringFunc(text, func(m string) string {
+		sub := restoreRe.FindStringSubmatch(m)
+		if sub[3] != "" {
+			if unescape {
+				return entities[sub[3]]
+			}
+			return m
+		}
+		id := sub[1]
+		if id == "" {
+			id = sub[2]
+		}
+		i, err := strconv.Atoi(id)
+		if err != nil || i >= len(values) {
+			return m
+		}
+		return values[i]
+	})
+}
+
+// Count returns how many placeholders re finds in text.
+func Count(text string, re *regexp.Regexp) int {
+	if re == nil {
+		return 0
+	}
+	return len(re.FindAllStringIndex(text, -1))
+}
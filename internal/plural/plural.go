@@ -0,0 +1,80 @@
+// Package plural knows the CLDR cardinal plural categories of the languages
+// supported by DeepL.
+package plural
+
+import "strings"
+
+// Canonical plural category order.
+var order = []string{"zero", "one", "two", "few", "many", "other"}
+
+var categories = map[string][]string{
+	"ar": {"zero", "one", "two", "few", "many", "other"},
+	"cs": {"one", "few", "many", "other"},
+	"es": {"one", "many", "other"},
+	"fr": {"one", "many", "other"},
+	"he": {"one", "two", "other"},
+	"id": {"other"},
+	"it": {"one", "many", "other"},
+	"ja": {"other"},
+	"ko": {"other"},
+	"lt": {"one", "few", "many", "other"},
+	"lv": {"zero", "one", "other"},
+	"pl": {"one", "few", "many", "other"},
+	"pt": {"one", "many", "other"},
+	"ro": {"one", "few", "other"},
+	"ru": {"one", "few", "many", "other"},
+	"sk": {"one", "few", "many", "other"},
+	"sl": {"one", "two", "few", "other"},
+	"th": {"other"},
+	"uk": {"one", "few", "many", "other"},
+	"vi": {"other"},
+	"zh": {"other"},
+}
+
+// Categories returns the plural categories of a language code such as
+// "RU" or "pt-BR", in canonical order. Unknown languages get one/other.
+func Categories(lang string) []string {
+	base := strings.ToLower(lang)
+	if i := strings.IndexAny(base, "-_"); i >= 0 {
+		base = base[:i]
+	}
+	if c, ok := categories[base]; ok {
+		return c
+	}
+	return []string{"one", "other"}
+}
+
+// IsCategory reports whether s is a CLDR plural category name.
+func IsCategory(s string) bool {
+	return Index(s) >= 0
+}
+
+// Index returns the canonical position of a category, or -1.
+func Index(category string) int {
+	for i, c := range order {
+		if c == category {
+			return i
+		}
+	}
+	return -1
+}
+
+// Merge returns the union of two category lists in canonical order.
+func Merge(a, b []string) []string {
+	var out []string
+	for _, c := range order {
+		if contains(a, c) || contains(b, c) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
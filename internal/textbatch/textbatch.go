@@ -0,0 +1,61 @@
+// Package textbatch splits large lists of texts into requests that stay
+// within the DeepL /translate limits.
+package textbatch
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+const (
+	// MaxTexts is the maximum number of text parameters per request.
+	MaxTexts = 50
+	// MaxBytes is the request size limit minus some headroom for the other parameters.
+	MaxBytes = 120 * 1024
+)
+
+// Split groups texts into batches that respect MaxTexts and MaxBytes.
+// A single text larger than MaxBytes is put in a batch of its own.
+func Split(texts []string) [][]string {
+	var batches [][]string
+	var current []string
+	size := 0
+
+	for _, text := range texts {
+		if len(current) > 0 && (len(current) == MaxTexts || size+len(text) > MaxBytes) {
+			batches = append(batches, current)
+			current = nil
+			size = 0
+		}
+		current = append(current, text)
+		size += len(text)
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+
+	return batches
+}
+
+// Translate translates texts using req as a template for every request.
+// The Text field of req is ignored. Results are returned in input order.
+func Translate(ctx context.Context, t godeeplapi.Translator, req models.TranslationRequest, texts []string) ([]string, error) {
+	results := make([]string, 0, len(texts))
+
+	for _, batch := range Split(texts) {
+		req.Text = batch
+		translated, err := t.Translate(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if len(translated) != len(batch) {
+			return nil, fmt.Errorf("expected %d translations, got %d", len(batch), len(translated))
+		}
+		results = append(results, translated...)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,267 @@
+package godeeplapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+// DefaultLanguageTTL is how long a LanguageRegistry keeps the language lists
+// when no TTL is given.
+const DefaultLanguageTTL = 24 * time.Hour
+
+// languageRetryDelay is how long a failed fetch is remembered before the
+// lists are fetched again.
+const languageRetryDelay = time.Minute
+
+// LanguageRegistry caches the source and target languages and the glossary
+// language pairs reported by the API. Lists are fetched on first use and
+// again once they are older than the TTL; concurrent callers share a single
+// fetch. When a fetch fails, the error is returned for a minute before the
+// lists are fetched again, and expired lists are still used meanwhile. It is
+// safe for concurrent use.
+type LanguageRegistry struct {
+	client *Client
+	ttl    time.Duration
+
+	mu       sync.Mutex
+	set      *languageSet
+	fetched  time.Time
+	loading  *languageFetch
+	err      error // error of the last fetch
+	failedAt time.Time
+}
+
+// languageFetch is a running fetch shared by concurrent callers.
+type languageFetch struct {
+	done chan struct{}
+	err  error
+}
+
+// NewLanguageRegistry creates a registry that fetches the languages with
+// client. A ttl <= 0 means DefaultLanguageTTL.
+func NewLanguageRegistry(client *Client, ttl time.Duration) *LanguageRegistry {
+	if ttl <= 0 {
+		ttl = DefaultLanguageTTL
+	}
+	return &LanguageRegistry{client: client, ttl: ttl}
+}
+
+// Refresh fetches the language lists regardless of their age.
+func (r *LanguageRegistry) Refresh(ctx context.Context) error {
+	return r.refresh(ctx)
+}
+
+// refresh fetches the lists, or waits for the fetch already running. r.mu is
+// not held during the requests.
+func (r *LanguageRegistry) refresh(ctx context.Context) error {
+	for {
+		r.mu.Lock()
+		running := r.loading
+		if running == nil {
+			break
+		}
+		r.mu.Unlock()
+
+		select {
+		case <-running.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		// Fetch again when only the context of the other caller ended
+		if !errors.Is(running.err, context.Canceled) && !errors.Is(running.err, context.DeadlineExceeded) {
+			return running.err
+		}
+	}
+	running := &languageFetch{done: make(chan struct{})}
+	r.loading = running
+	r.mu.Unlock()
+
+	set, err := r.fetch(ctx)
+
+	r.mu.Lock()
+	switch {
+	case err == nil:
+		r.set, r.fetched, r.err = set, time.Now(), nil
+	case ctx.Err() == nil:
+		// Cancelled fetches are not remembered as failures
+		r.err, r.failedAt = err, time.Now()
+	}
+	r.loading = nil
+	r.mu.Unlock()
+	running.err = err
+	close(running.done)
+	return err
+}
+
+func (r *LanguageRegistry) fetch(ctx context.Context) (*languageSet, error) {
+	sources, err := r.client.GetSourceLanguages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching source languages: %w", err)
+	}
+	targets, err := r.client.GetTargetLanguages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching target languages: %w", err)
+	}
+	pairs, err := r.client.ListLangPairsSupportedByGlossaries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching glossary language pairs: %w", err)
+	}
+
+	set := &languageSet{
+		sources:       make(map[models.Language]models.SupportedLanguage, len(sources)),
+		targets:       make(map[models.Language]models.SupportedLanguage, len(targets)),
+		glossaryPairs: make(map[[2]models.Language]bool, len(pairs.SupportedLanguages)),
+	}
+	for _, l := range sources {
+		set.sources[models.Language(l.Language).SourceCode()] = l
+	}
+	for _, l := range targets {
+		set.targets[models.Language(l.Language).TargetCode()] = l
+	}
+	for _, p := range pairs.SupportedLanguages {
+		set.glossaryPairs[[2]models.Language{p.SourceLanguage.SourceCode(), p.TargetLanguage.SourceCode()}] = true
+	}
+
+	r.client.logger.Debug("Loaded %d source and %d target languages", len(sources), len(targets))
+	return set, nil
+}
+
+// load returns the cached lists, fetching them when missing or expired.
+// Expired lists are returned when the fetch fails.
+func (r *LanguageRegistry) load(ctx context.Context) (*languageSet, error) {
+	r.mu.Lock()
+	set, fetched := r.set, r.fetched
+	lastErr, failedAt := r.err, r.failedAt
+	r.mu.Unlock()
+
+	if set != nil && time.Since(fetched) <= r.ttl {
+		return set, nil
+	}
+	err := lastErr
+	if err == nil || time.Since(failedAt) >= languageRetryDelay {
+		if err = r.refresh(ctx); err == nil {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			return r.set, nil
+		}
+	}
+	if set != nil {
+		r.client.logger.Debug("Using expired language lists: %v", err)
+		return set, nil
+	}
+	return nil, err
+}
+
+// SourceLanguages returns the supported source languages ordered by code.
+func (r *LanguageRegistry) SourceLanguages(ctx context.Context) ([]models.SupportedLanguage, error) {
+	set, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sortedLanguages(set.sources), nil
+}
+
+// TargetLanguages returns the supported target languages ordered by code.
+func (r *LanguageRegistry) TargetLanguages(ctx context.Context) ([]models.SupportedLanguage, error) {
+	set, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sortedLanguages(set.targets), nil
+}
+
+// IsValidSource reports whether lang can be translated from. Target variants
+// such as EN-GB count as their source code.
+func (r *LanguageRegistry) IsValidSource(ctx context.Context, lang models.Language) (bool, error) {
+	set, err := r.load(ctx)
+	if err != nil {
+		return false, err
+	}
+	return set.IsValidSource(lang), nil
+}
+
+// IsValidTarget reports whether lang can be translated into. Bare codes
+// such as EN count as their default variant.
+func (r *LanguageRegistry) IsValidTarget(ctx context.Context, lang models.Language) (bool, error) {
+	set, err := r.load(ctx)
+	if err != nil {
+		return false, err
+	}
+	return set.IsValidTarget(lang), nil
+}
+
+// SupportsFormality reports whether the formality parameter can be used
+// with the target language lang.
+func (r *LanguageRegistry) SupportsFormality(ctx context.Context, lang models.Language) (bool, error) {
+	set, err := r.load(ctx)
+	if err != nil {
+		return false, err
+	}
+	return set.SupportsFormality(lang), nil
+}
+
+// SupportsGlossaryPair reports whether glossaries can be created for the
+// language pair.
+func (r *LanguageRegistry) SupportsGlossaryPair(ctx context.Context, source, target models.Language) (bool, error) {
+	set, err := r.load(ctx)
+	if err != nil {
+		return false, err
+	}
+	return set.SupportsGlossaryPair(source, target), nil
+}
+
+// languageSupport returns the cached lists for request validation, or nil
+// when they cannot be fetched so that validation falls back to the
+// built-in checks.
+func (r *LanguageRegistry) languageSupport(ctx context.Context) models.LanguageSupport {
+	set, err := r.load(ctx)
+	if err != nil {
+		r.client.logger.Error("Skipping language validation: %v", err)
+		return nil
+	}
+	return set
+}
+
+// languageSet is a snapshot of the language lists.
+type languageSet struct {
+	sources       map[models.Language]models.SupportedLanguage
+	targets       map[models.Language]models.SupportedLanguage
+	glossaryPairs map[[2]models.Language]bool
+}
+
+var _ models.LanguageSupport = (*languageSet)(nil)
+
+func (s *languageSet) IsValidSource(lang models.Language) bool {
+	_, ok := s.sources[lang.SourceCode()]
+	return ok
+}
+
+func (s *languageSet) IsValidTarget(lang models.Language) bool {
+	_, ok := s.targets[lang.TargetCode()]
+	return ok
+}
+
+func (s *languageSet) SupportsFormality(lang models.Language) bool {
+	return s.targets[lang.TargetCode()].SupportsFormality
+}
+
+func (s *languageSet) SupportsGlossaryPair(source, target models.Language) bool {
+	return s.glossaryPairs[[2]models.Language{source.SourceCode(), target.SourceCode()}]
+}
+
+func sortedLanguages(m map[models.Language]models.SupportedLanguage) []models.SupportedLanguage {
+	languages := make([]models.SupportedLanguage, 0, len(m))
+	for _, l := range m {
+		languages = append(languages, l)
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		return languages[i].Language < languages[j].Language
+	})
+	return languages
+}
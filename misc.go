@@ -26,11 +26,26 @@ func (c *Client) GetUsageAndLimits(ctx context.Context) (*models.UsageAndLimitRe
 
 // GetLanguages returns struct with all supported languages
 func (c *Client) GetLanguages(ctx context.Context) ([]models.SupportedLanguage, error) {
+	return c.getLanguages(ctx, "")
+}
+
+// GetSourceLanguages returns the languages that can be translated from
+func (c *Client) GetSourceLanguages(ctx context.Context) ([]models.SupportedLanguage, error) {
+	return c.getLanguages(ctx, models.LanguageTypeSource)
+}
+
+// GetTargetLanguages returns the languages that can be translated into and
+// whether they support formality
+func (c *Client) GetTargetLanguages(ctx context.Context) ([]models.SupportedLanguage, error) {
+	return c.getLanguages(ctx, models.LanguageTypeTarget)
+}
+
+func (c *Client) getLanguages(ctx context.Context, langType string) ([]models.SupportedLanguage, error) {
 	err := c.checkAuth()
 	if err != nil {
 		return nil, err
 	}
-	respBody, err := c.doRequestWithQuery(ctx, "GET", "/languages", nil, nil, nil)
+	respBody, err := c.doRequestWithQuery(ctx, "GET", "/languages", nil, nil, models.LanguagesRequest{Type: langType})
 	if err != nil {
 		return nil, err
 	}
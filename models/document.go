@@ -1,20 +1,35 @@
 package models
 
-import "io"
+import (
+	"io"
+	"path/filepath"
+	"strings"
+)
 
 // FileTranslationRequest represents parameters for a document translation request.
 type FileTranslationRequest struct {
 	// Language of the text to be translated.
 	// If omitted, the API will attempt to detect the language.
-	SourceLang string `json:"source_lang,omitempty"`
+	SourceLang Language `json:"source_lang,omitempty"`
 
 	// The language into which the text should be translated.
-	TargetLang string `json:"target_lang"`
+	TargetLang Language `json:"target_lang"`
 
 	// The document file to be translated.
 	// This is handled separately in the multipart form upload.
 	File io.Reader `json:"-"`
 
+	// Optional. Size of File in bytes. When zero it is detected for files,
+	// byte readers and other seekers, and the upload is sent without a
+	// Content-Length otherwise.
+	FileSize int64 `json:"-"`
+
+	// Optional. Called with the progress of the upload, the translation and
+	// the download. Upload progress is reported from the uploading goroutine
+	// with the bytes of File sent so far. UploadDocument passes it on in the
+	// returned handle.
+	OnProgress func(DocumentProgress) `json:"-"`
+
 	// The name of the uploaded file.
 	FileName string `json:"filename,omitempty"`
 
@@ -28,12 +43,102 @@ type FileTranslationRequest struct {
 	GlossaryId string `json:"glossary_id,omitempty"`
 }
 
+// DocumentSizeLimits are the maximum upload sizes in bytes of the document
+// formats, by file extension.
+var DocumentSizeLimits = map[string]int64{
+	".docx":  30 << 20,
+	".doc":   30 << 20,
+	".pptx":  30 << 20,
+	".xlsx":  30 << 20,
+	".pdf":   30 << 20,
+	".htm":   5 << 20,
+	".html":  5 << 20,
+	".txt":   1 << 20,
+	".xlf":   10 << 20,
+	".xliff": 10 << 20,
+	".srt":   150 << 10,
+}
+
+// DocumentSizeLimit returns the maximum upload size of a file, by its
+// extension. ok is false for formats without a known limit.
+func DocumentSizeLimit(fileName string) (limit int64, ok bool) {
+	limit, ok = DocumentSizeLimits[strings.ToLower(filepath.Ext(fileName))]
+	return limit, ok
+}
+
 // DocumentResponse represents the initial response when uploading a document.
 type DocumentResponse struct {
 	DocumentId  string `json:"document_id"`
 	DocumentKey string `json:"document_key"`
 }
 
+// DocumentHandle identifies an uploaded document. It can be stored, e.g. as
+// JSON, to check the status and download the result after a restart. The
+// document key is a secret.
+type DocumentHandle struct {
+	DocumentId  string `json:"document_id"`
+	DocumentKey string `json:"document_key"`
+
+	// FileName is the name of the uploaded file, used when the result has
+	// no file name.
+	FileName string `json:"filename,omitempty"`
+
+	// TargetLang is the language the document is translated into.
+	TargetLang Language `json:"target_lang,omitempty"`
+
+	// OutputFormat is the file extension of the result when it differs from
+	// the uploaded file, e.g. "pdf".
+	OutputFormat string `json:"output_format,omitempty"`
+
+	// OnProgress is called with the progress of the translation and the
+	// download. It is not stored; set it again on a restored handle.
+	OnProgress func(DocumentProgress) `json:"-"`
+}
+
+// DocumentStage is a step of a document translation.
+type DocumentStage string
+
+// Document stages reported in DocumentProgress
+const (
+	DocumentStageUploading   DocumentStage = "uploading"
+	DocumentStageQueued      DocumentStage = "queued"
+	DocumentStageTranslating DocumentStage = "translating"
+	DocumentStageDone        DocumentStage = "done"
+	DocumentStageDownloading DocumentStage = "downloading"
+	DocumentStageDownloaded  DocumentStage = "downloaded"
+	DocumentStageError       DocumentStage = "error"
+)
+
+// DocumentProgress reports the progress of a document translation.
+type DocumentProgress struct {
+	// Stage is one of the DocumentStage constants. While translating it is
+	// the document status of the API.
+	Stage DocumentStage
+	// DocumentId is empty while uploading.
+	DocumentId string
+
+	// Bytes and Total are the bytes transferred so far and the size of the
+	// file while uploading and downloading. Total is -1 if unknown.
+	Bytes int64
+	Total int64
+
+	// SecondsRemaining is the estimate of DeepL while translating.
+	SecondsRemaining int
+	// BilledCharacters is set when the translation is done.
+	BilledCharacters int
+}
+
+// DocumentResult describes a downloaded document.
+type DocumentResult struct {
+	// FileName is the file name sent by the API, or else the name of the
+	// uploaded file.
+	FileName string
+	// ContentType is the media type sent by the API.
+	ContentType string
+	// Size is the number of bytes, or -1 if unknown before reading.
+	Size int64
+}
+
 // DocumentStatusResponse represents the status of a document translation.
 type DocumentStatusResponse struct {
 	DocumentId       string `json:"document_id"`
@@ -50,3 +155,17 @@ var (
 	DocumentStatusDone        = "done"
 	DocumentStatusError       = "error"
 )
+
+// ResultFileName returns the expected file name of the translated document:
+// the base name of FileName with the extension replaced by OutputFormat, if
+// set. It returns "" when FileName is empty.
+func (h DocumentHandle) ResultFileName() string {
+	if h.FileName == "" {
+		return ""
+	}
+	name := filepath.Base(strings.ReplaceAll(h.FileName, "\\", "/"))
+	if h.OutputFormat != "" {
+		name = strings.TrimSuffix(name, filepath.Ext(name)) + "." + strings.TrimPrefix(h.OutputFormat, ".")
+	}
+	return name
+}
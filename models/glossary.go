@@ -8,17 +8,17 @@ type GlossaryListResponse struct {
 // GlossaryLangPair represents a source-target language pair supported by glossaries.
 type GlossaryLangPair struct {
 	// The language for source texts. Use SourceLanguageCode struct for options.
-	SourceLanguage string `json:"source_lang"`
+	SourceLanguage Language `json:"source_lang"`
 	// The language for target texts. Use TargetLanguageCode struct for options.
-	TargetLanguage string `json:"target_lang"`
+	TargetLanguage Language `json:"target_lang"`
 }
 
 // Dictionary represents a single language pair dictionary within a glossary.
 type Dictionary struct {
 	// The language for source texts.
-	SourceLanguage string `json:"source_lang"`
+	SourceLanguage Language `json:"source_lang"`
 	// The language for target texts.
-	TargetLanguage string `json:"target_lang"`
+	TargetLanguage Language `json:"target_lang"`
 	// Optional. The entries of the glossary in the specified format.
 	Entries string `json:"entries,omitempty"`
 	// Optional. Format: "tsv" (default) or "csv".
@@ -70,9 +70,9 @@ type GlossaryEntriesResponse struct {
 // EditOrCreateDictionaryInGlossaryResponse represents answer from API.
 type EditOrCreateDictionaryInGlossaryResponse struct {
 	//The language in which the source texts in the glossary are specified.
-	SourceLanguage string `json:"source_lang,omitempty"`
+	SourceLanguage Language `json:"source_lang,omitempty"`
 	//The language in which the target texts in the glossary are specified.
-	TargetLanguage string `json:"target_lang,omitempty"`
+	TargetLanguage Language `json:"target_lang,omitempty"`
 	//The number of entries in the glossary.
 	EntryCount int `json:"entry_count,omitempty"`
 }
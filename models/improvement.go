@@ -6,7 +6,7 @@ type RephraseRequest struct {
 	Text []string `json:"text"`
 
 	// The language for the text improvement.
-	TargetLanguage string `json:"target_lang,omitempty"`
+	TargetLanguage Language `json:"target_lang,omitempty"`
 
 	// Specify a style to rephrase your text.
 	WritingStyle string `json:"writing_style,omitempty"`
@@ -17,8 +17,13 @@ type RephraseRequest struct {
 
 // RephraseResponse represents the response from the text improvement API.
 type RephraseResponse struct {
+	Improvements []Improvement `json:"improvements"`
+}
+
+// Improvement is the improved version of one text of a RephraseRequest.
+type Improvement struct {
 	Text           string `json:"text"`
-	TargetLanguage string `json:"target_lang"`
+	TargetLanguage string `json:"target_language"`
 	SourceLanguage string `json:"detected_source_language"`
 }
 
@@ -47,29 +52,3 @@ var (
 	TonePreferEnthusiastic = "prefer_enthusiastic"
 	TonePreferFriendly     = "prefer_friendly"
 )
-
-// ImproveTextLanguage defines language codes for the improve text API.
-type ImproveTextLanguage struct {
-	German       string
-	EnglishUS    string
-	EnglishGB    string
-	English      string
-	Spanish      string
-	French       string
-	Italian      string
-	PortugueseBR string
-	Portuguese   string
-}
-
-// ImproveTextLanguages provides language codes for text improvement.
-var ImproveTextLanguages = ImproveTextLanguage{
-	German:       "de",
-	EnglishUS:    "en",
-	EnglishGB:    "en-GB",
-	English:      "en-US",
-	Spanish:      "es",
-	French:       "fr",
-	Italian:      "it",
-	PortugueseBR: "pt-BR",
-	Portuguese:   "pt",
-}
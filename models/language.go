@@ -0,0 +1,143 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+//go:generate go run ../internal/cmd/genlanguages -snapshot languages.json -out languages.go
+
+// Language is a DeepL language code such as "DE", "EN-US" or "ZH-HANS".
+// Source languages are always bare codes, while some target languages are
+// only available as a regional or script variant.
+type Language string
+
+// defaultTargets maps the bare codes that are not valid target languages to
+// the variant used by default.
+var defaultTargets = map[Language]Language{
+	"EN": "EN-US",
+	"PT": "PT-PT",
+}
+
+// ParseLanguage parses a BCP 47 tag such as "en-GB", "pt_BR" or "zh-Hant-TW".
+// Regions and scripts that DeepL has no variant for are dropped, so "de-AT"
+// becomes "DE".
+func ParseLanguage(s string) (Language, error) {
+	tag, err := language.Parse(strings.ReplaceAll(strings.TrimSpace(s), "_", "-"))
+	if err != nil {
+		return "", fmt.Errorf("invalid language %q: %w", s, err)
+	}
+	return LanguageFromTag(tag)
+}
+
+// LanguageFromTag converts a language tag to the matching DeepL code.
+func LanguageFromTag(tag language.Tag) (Language, error) {
+	base, confidence := tag.Base()
+	if confidence == language.No {
+		return "", fmt.Errorf("language %q has no base language", tag)
+	}
+
+	code := Language(strings.ToUpper(base.String()))
+	if code == "NO" {
+		// DeepL uses Norwegian Bokmål for Norwegian.
+		code = "NB"
+	}
+
+	script, scriptConfidence := tag.Script()
+	region, regionConfidence := tag.Region()
+	if code == "ZH" {
+		if scriptConfidence != language.Exact && regionConfidence != language.Exact {
+			return code, nil
+		}
+		return code + "-" + Language(strings.ToUpper(script.String())), nil
+	}
+	if regionConfidence == language.Exact {
+		if variant := code + "-" + Language(strings.ToUpper(region.String())); targetVariants[variant] {
+			return variant, nil
+		}
+	}
+	return code, nil
+}
+
+// String returns the code as a string.
+func (l Language) String() string {
+	return string(l)
+}
+
+// Tag returns the BCP 47 tag of the language.
+func (l Language) Tag() language.Tag {
+	return language.Make(string(l))
+}
+
+// IsVariant reports whether l is a regional or script variant, e.g. "EN-GB".
+func (l Language) IsVariant() bool {
+	return strings.Contains(string(l), "-")
+}
+
+// Locale returns l in the usual locale form with its parts joined by sep:
+// the language in lower case, a script in title case and a region in upper
+// case. "PT-BR" becomes "pt-BR" with "-" and "pt_BR" with "_", "ZH-HANT"
+// becomes "zh-Hant".
+func (l Language) Locale(sep string) string {
+	parts := strings.Split(string(l), "-")
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) == 4 {
+			parts[i] = strings.ToUpper(parts[i][:1]) + strings.ToLower(parts[i][1:])
+		} else {
+			parts[i] = strings.ToUpper(parts[i])
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+// SourceCode returns the source language for l: variants are reduced to
+// their base code, so "EN-US" and "PT-BR" become "EN" and "PT".
+func (l Language) SourceCode() Language {
+	base, _, _ := strings.Cut(string(l), "-")
+	return Language(strings.ToUpper(base))
+}
+
+// TargetCode returns the target language for l: bare codes that are only
+// available as variants get their default variant, so "EN" becomes "EN-US"
+// and "PT" becomes "PT-PT". Other codes are only upper-cased.
+func (l Language) TargetCode() Language {
+	code := Language(strings.ToUpper(string(l)))
+	if variant, ok := defaultTargets[code]; ok {
+		return variant
+	}
+	return code
+}
+
+// SupportsFormality reports whether the target language l supports the
+// formality parameter, according to the built-in language table.
+func (l Language) SupportsFormality() bool {
+	return formalityLanguages[l.TargetCode()]
+}
+
+// SupportedLanguage represents a language supported by DeepL API.
+type SupportedLanguage struct {
+	Language          string `json:"language"`
+	Name              string `json:"name"`
+	SupportsFormality bool   `json:"supports_formality,omitempty"`
+}
+
+// LanguagesResponse represents the response from the languages API.
+type LanguagesResponse struct {
+	Languages []SupportedLanguage `json:"languages"`
+}
+
+// Language list types for the languages endpoint
+var (
+	LanguageTypeSource = "source"
+	LanguageTypeTarget = "target"
+)
+
+// LanguagesRequest holds the query parameters of the languages endpoint.
+type LanguagesRequest struct {
+	// Type selects the source or the target languages. The API returns the
+	// source languages when it is empty.
+	Type string `json:"type,omitempty"`
+}
@@ -9,11 +9,11 @@ type TranslationRequest struct {
 	Text []string `json:"text"`
 
 	// The language into which the text should be translated.
-	TargetLang string `json:"target_lang"`
+	TargetLang Language `json:"target_lang"`
 
 	// Language of the text to be translated.
 	// If this parameter is omitted, the API will attempt to detect the language of the text and translate it.
-	SourceLang string `json:"source_lang,omitempty"`
+	SourceLang Language `json:"source_lang,omitempty"`
 
 	// Additional context that can influence a translation but is not translated itself.
 	// Characters included in the context parameter will not be counted toward billing.
@@ -72,10 +72,25 @@ type TranslationRequest struct {
 
 // TranslationResponse represents the response from the translation API.
 type TranslationResponse struct {
-	Translations []struct {
-		DetectedSourceLanguage string `json:"detected_source_language"`
-		Text                   string `json:"text"`
-	} `json:"translations"`
+	Translations []Translation `json:"translations"`
+}
+
+// Translation is a single translated text.
+type Translation struct {
+	DetectedSourceLanguage string `json:"detected_source_language"`
+	Text                   string `json:"text"`
+
+	// FromMemory is set when the text was served from a translation memory
+	// instead of the API.
+	FromMemory bool `json:"-"`
+
+	// MemoryScore is the similarity of the memory entry that was used,
+	// 1 for exact matches.
+	MemoryScore float64 `json:"-"`
+
+	// MemoryMatches are fuzzy translation memory matches returned alongside
+	// an API translation, best first.
+	MemoryMatches []MemoryMatch `json:"-"`
 }
 
 // Option constants for formality
@@ -106,3 +121,11 @@ var (
 	SplitDefault    = "1"
 	SplitNoNewlines = "nonewlines"
 )
+
+// MemoryMatch is a translation memory entry similar to a requested text.
+type MemoryMatch struct {
+	Source string
+	Target string
+	// Score is the similarity between 0 and 1, where 1 is an exact match.
+	Score float64
+}
@@ -0,0 +1,264 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError describes a single invalid request field.
+type FieldError struct {
+	// Field is the name of the struct field, e.g. "GlossaryId".
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError is returned by the Validate methods and lists every invalid
+// field of a request, so that all problems can be fixed at once.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Error()
+	}
+	return "invalid request: " + strings.Join(msgs, "; ")
+}
+
+// Has reports whether field is among the invalid fields.
+func (e *ValidationError) Has(field string) bool {
+	for _, fe := range e.Errors {
+		if fe.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// LanguageSupport reports which languages and features the API supports.
+// The root package's LanguageRegistry implements it from the live language
+// lists; without one, Validate falls back to the built-in formality list.
+type LanguageSupport interface {
+	IsValidSource(lang Language) bool
+	IsValidTarget(lang Language) bool
+	SupportsFormality(lang Language) bool
+	SupportsGlossaryPair(source, target Language) bool
+}
+
+// validator collects field errors.
+type validator struct {
+	langs LanguageSupport
+	errs  []FieldError
+}
+
+func (v *validator) add(field, format string, args ...any) {
+	v.errs = append(v.errs, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) required(field, value string) {
+	if value == "" {
+		v.add(field, "is required")
+	}
+}
+
+func (v *validator) oneOf(field, value string, allowed ...string) {
+	if value == "" {
+		return
+	}
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.add(field, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+}
+
+func (v *validator) formality(value string, targetLang Language) {
+	v.oneOf("Formality", value, FormalityDefault, FormalityMore, FormalityLess, FormalityPreferMore, FormalityPreferLess)
+	if (value == FormalityMore || value == FormalityLess) && targetLang != "" && !v.supportsFormality(targetLang) {
+		v.add("Formality", "is not supported for target language %s; use %s or %s instead",
+			targetLang, FormalityPreferMore, FormalityPreferLess)
+	}
+}
+
+func (v *validator) supportsFormality(targetLang Language) bool {
+	if v.langs != nil {
+		return v.langs.SupportsFormality(targetLang)
+	}
+	return targetLang.SupportsFormality()
+}
+
+// languages checks the languages against the supported lists, if known.
+func (v *validator) languages(sourceLang, targetLang Language) {
+	if v.langs == nil {
+		return
+	}
+	if sourceLang != "" && !v.langs.IsValidSource(sourceLang) {
+		v.add("SourceLang", "%s is not a supported source language", sourceLang)
+	}
+	if targetLang != "" && !v.langs.IsValidTarget(targetLang) {
+		v.add("TargetLang", "%s is not a supported target language", targetLang)
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: v.errs}
+}
+
+// Validate checks the request for combinations that the API would reject.
+func (r TranslationRequest) Validate() error {
+	return r.ValidateWith(nil)
+}
+
+// ValidateWith is like Validate, but also checks the languages against langs.
+func (r TranslationRequest) ValidateWith(langs LanguageSupport) error {
+	v := validator{langs: langs}
+	if len(r.Text) == 0 {
+		v.add("Text", "must contain at least one text")
+	}
+	v.required("TargetLang", string(r.TargetLang))
+	v.languages(r.SourceLang, r.TargetLang)
+	if r.GlossaryId != "" && r.SourceLang == "" {
+		v.add("GlossaryId", "requires SourceLang to be set")
+	}
+	v.formality(r.Formality, r.TargetLang)
+	v.oneOf("SplitSentences", r.SplitSentences, SplitNone, SplitDefault, SplitNoNewlines)
+	v.oneOf("ModelType", r.ModelType, ModelQuality, ModelPreferQuality, ModelLatency)
+	v.oneOf("TagHandling", r.TagHandling, TagXML, TagHTML)
+	if r.TagHandling == "" {
+		if len(r.IgnoreTags) > 0 {
+			v.add("IgnoreTags", "requires TagHandling to be set")
+		}
+		if len(r.SplittingTags) > 0 {
+			v.add("SplittingTags", "requires TagHandling to be set")
+		}
+		if len(r.NonSplittingTags) > 0 {
+			v.add("NonSplittingTags", "requires TagHandling to be set")
+		}
+	}
+	return v.err()
+}
+
+// Validate checks the request for combinations that the API would reject.
+func (r FileTranslationRequest) Validate() error {
+	return r.ValidateWith(nil)
+}
+
+// ValidateWith is like Validate, but also checks the languages against langs.
+func (r FileTranslationRequest) ValidateWith(langs LanguageSupport) error {
+	v := validator{langs: langs}
+	if r.File == nil {
+		v.add("File", "is required")
+	}
+	v.required("TargetLang", string(r.TargetLang))
+	v.languages(r.SourceLang, r.TargetLang)
+	if r.GlossaryId != "" && r.SourceLang == "" {
+		v.add("GlossaryId", "requires SourceLang to be set")
+	}
+	v.formality(r.Formality, r.TargetLang)
+	return v.err()
+}
+
+// Validate checks the request for combinations that the API would reject.
+func (r RephraseRequest) Validate() error {
+	var v validator
+	if len(r.Text) == 0 {
+		v.add("Text", "must contain at least one text")
+	}
+	v.oneOf("WritingStyle", r.WritingStyle, StyleDefault, StyleAcademic, StyleBusiness, StyleCasual, StyleSimple,
+		StylePreferAcademic, StylePreferBusiness, StylePreferCasual, StylePreferSimple)
+	v.oneOf("Tone", r.Tone, ToneDefault, ToneConfident, ToneDiplomatic, ToneEnthusiastic, ToneFriendly,
+		TonePreferConfident, TonePreferDiplomatic, TonePreferEnthusiastic, TonePreferFriendly)
+	if r.WritingStyle != "" && r.Tone != "" {
+		v.add("Tone", "cannot be combined with WritingStyle")
+	}
+	return v.err()
+}
+
+// Validate checks that both languages are set.
+func (p GlossaryLangPair) Validate() error {
+	var v validator
+	p.validate(&v, "")
+	return v.err()
+}
+
+func (p GlossaryLangPair) validate(v *validator, prefix string) {
+	v.required(prefix+"SourceLanguage", string(p.SourceLanguage))
+	v.required(prefix+"TargetLanguage", string(p.TargetLanguage))
+}
+
+// Validate checks the dictionary for values that the API would reject.
+func (d Dictionary) Validate() error {
+	return d.ValidateWith(nil)
+}
+
+// ValidateWith is like Validate, but also checks that langs supports the
+// language pair for glossaries.
+func (d Dictionary) ValidateWith(langs LanguageSupport) error {
+	v := validator{langs: langs}
+	d.validate(&v, "")
+	return v.err()
+}
+
+func (d Dictionary) validate(v *validator, prefix string) {
+	GlossaryLangPair{SourceLanguage: d.SourceLanguage, TargetLanguage: d.TargetLanguage}.validate(v, prefix)
+	if d.SourceLanguage != "" && d.SourceLanguage.SourceCode() == d.TargetLanguage.SourceCode() {
+		v.add(prefix+"TargetLanguage", "must differ from SourceLanguage")
+	}
+	if strings.TrimSpace(d.Entries) == "" {
+		v.add(prefix+"Entries", "must contain at least one entry")
+	}
+	v.oneOf(prefix+"EntriesFormat", d.EntriesFormat, "tsv", "csv")
+	if v.langs != nil && d.SourceLanguage != "" && d.TargetLanguage != "" &&
+		!v.langs.SupportsGlossaryPair(d.SourceLanguage, d.TargetLanguage) {
+		v.add(prefix+"TargetLanguage", "glossaries do not support %s to %s", d.SourceLanguage, d.TargetLanguage)
+	}
+}
+
+// Validate checks the request for values that the API would reject.
+func (r CreateGlossaryRequest) Validate() error {
+	return r.ValidateWith(nil)
+}
+
+// ValidateWith is like Validate, but also checks that langs supports the
+// language pairs of the dictionaries for glossaries.
+func (r CreateGlossaryRequest) ValidateWith(langs LanguageSupport) error {
+	v := validator{langs: langs}
+	v.required("Name", r.Name)
+	if len(r.Dictionaries) == 0 {
+		v.add("Dictionaries", "must contain at least one dictionary")
+	}
+	for i, d := range r.Dictionaries {
+		d.validate(&v, fmt.Sprintf("Dictionaries[%d].", i))
+	}
+	return v.err()
+}
+
+// Validate checks the request for values that the API would reject.
+func (r EditGlossaryRequest) Validate() error {
+	return r.ValidateWith(nil)
+}
+
+// ValidateWith is like Validate, but also checks that langs supports the
+// language pair of the dictionary for glossaries.
+func (r EditGlossaryRequest) ValidateWith(langs LanguageSupport) error {
+	v := validator{langs: langs}
+	if r.Name == "" && len(r.Dictionaries) == 0 {
+		v.add("Name", "is required when no dictionary is given")
+	}
+	if len(r.Dictionaries) > 1 {
+		v.add("Dictionaries", "can contain at most one dictionary, got %d", len(r.Dictionaries))
+	}
+	for i, d := range r.Dictionaries {
+		d.validate(&v, fmt.Sprintf("Dictionaries[%d].", i))
+	}
+	return v.err()
+}
@@ -0,0 +1,190 @@
+package godeeplapi
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+// CollisionPolicy decides what DownloadDocument does when the output file
+// already exists.
+type CollisionPolicy int
+
+const (
+	// CollisionOverwrite replaces the existing file.
+	CollisionOverwrite CollisionPolicy = iota
+	// CollisionSuffix keeps the existing file and adds " (1)", " (2)", ...
+	// to the name of the new one.
+	CollisionSuffix
+	// CollisionFail keeps the existing file and fails with ErrOutputExists.
+	CollisionFail
+)
+
+// DefaultOutputTemplate names downloaded documents like the file sent by
+// DeepL.
+const DefaultOutputTemplate = "{name}.{ext}"
+
+// defaultFileName is used when no usable file name is known.
+const defaultFileName = "translated_document"
+
+// maxSuffix is the highest suffix tried by CollisionSuffix.
+const maxSuffix = 1000
+
+// OutputOptions controls the files written by DownloadDocument.
+type OutputOptions struct {
+	// Template is the file name, DefaultOutputTemplate if empty. {name} is
+	// the file name without extension, {ext} the extension without the dot,
+	// {target} the lower-case target language and {id} the document ID,
+	// e.g. "{name}.{target}.{ext}" gives "manual.de.docx".
+	Template string
+	// Collision is the policy for existing files.
+	Collision CollisionPolicy
+}
+
+// outputName renders the template for a document. Every value and the
+// result are sanitized, so the name never leaves the target directory.
+func (o OutputOptions) outputName(fileName string, handle models.DocumentHandle) string {
+	template := o.Template
+	if template == "" {
+		template = DefaultOutputTemplate
+	}
+	fileName = sanitizeFileName(fileName)
+	ext := filepath.Ext(fileName)
+	name := strings.TrimSuffix(fileName, ext)
+
+	r := strings.NewReplacer(
+		"{name}", name,
+		"{ext}", strings.TrimPrefix(ext, "."),
+		"{target}", sanitizeFileName(strings.ToLower(handle.TargetLang.String())),
+		"{id}", sanitizeFileName(handle.DocumentId),
+	)
+	return sanitizeFileName(r.Replace(template))
+}
+
+// sanitizeFileName turns name into a plain file name: directories, control
+// characters and characters reserved on Windows are removed, as are leading
+// and trailing dots and spaces. Names of Windows devices such as CON or
+// nul.txt get a "_" prefix. It returns defaultFileName when nothing is left.
+func sanitizeFileName(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`<>:"|?*`, r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.Trim(name, ". ")
+	if name == "" {
+		return defaultFileName
+	}
+	base, _, _ := strings.Cut(name, ".")
+	if reservedNames[strings.ToUpper(strings.TrimRight(base, " "))] {
+		return "_" + name
+	}
+	return name
+}
+
+// reservedNames are the device names Windows reserves with any extension.
+var reservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true, "CONIN$": true, "CONOUT$": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"COM¹": true, "COM²": true, "COM³": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+	"LPT¹": true, "LPT²": true, "LPT³": true,
+}
+
+// createOutput creates the temporary file DownloadDocument writes to.
+func createOutput(dir string) (*os.File, error) {
+	tmp, err := os.CreateTemp(dir, ".deepl-*.part")
+	if err != nil {
+		return nil, fmt.Errorf("error creating output file: %w", err)
+	}
+	return tmp, nil
+}
+
+// writeOutput writes r to tmp, closes it and moves it to name in the same
+// directory according to the collision policy. The caller removes tmp, so
+// no partial output is left behind on failure. It returns the path of the
+// written file.
+func writeOutput(tmp *os.File, name string, policy CollisionPolicy, r io.Reader) (string, error) {
+	tmpPath := tmp.Name()
+
+	// Use buffered download for performance
+	buf := make([]byte, 64*1024) // 64KB buffer
+	if _, err := io.CopyBuffer(tmp, r, buf); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("error saving file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("error saving file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("error saving file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return "", fmt.Errorf("error saving file: %w", err)
+	}
+
+	dir := filepath.Dir(tmpPath)
+	path := filepath.Join(dir, name)
+	switch policy {
+	case CollisionSuffix:
+		ext := filepath.Ext(name)
+		base := strings.TrimSuffix(name, ext)
+		for i := 1; ; i++ {
+			err := moveNew(tmpPath, path)
+			if !errors.Is(err, os.ErrExist) {
+				return path, err
+			}
+			if i > maxSuffix {
+				return "", fmt.Errorf("%w: %s", ErrOutputExists, path)
+			}
+			path = filepath.Join(dir, base+" ("+strconv.Itoa(i)+")"+ext)
+		}
+	case CollisionFail:
+		if err := moveNew(tmpPath, path); errors.Is(err, os.ErrExist) {
+			return "", fmt.Errorf("%w: %s", ErrOutputExists, path)
+		} else if err != nil {
+			return "", err
+		}
+		return path, nil
+	default:
+		if err := os.Rename(tmpPath, path); err != nil {
+			return "", fmt.Errorf("error saving file: %w", err)
+		}
+		return path, nil
+	}
+}
+
+// moveNew moves from to a path that must not exist. It fails with an error
+// matching os.ErrExist if it does. A hard link makes the check atomic; on
+// file systems without hard links it falls back to a check and a rename.
+func moveNew(from, to string) error {
+	err := os.Link(from, to)
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, os.ErrExist) {
+		return err
+	}
+	if _, statErr := os.Lstat(to); statErr == nil {
+		return os.ErrExist
+	}
+	if err := os.Rename(from, to); err != nil {
+		return fmt.Errorf("error saving file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// Package pseudo implements an offline translator that pseudo-localizes text,
+// so that a localization pipeline and the UI can be tested without calling
+// DeepL. "Save changes" becomes "[Šàvé çĥàñĝéš~~~~]": accented letters show
+// strings that were not run through translation, the padding shows layouts
+// that break with longer languages and the brackets show truncation.
+package pseudo
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AdolfZahid1/godeeplapi/internal/placeholder"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+// DefaultProtected matches the parts of a text that are copied unchanged:
+// XML/HTML tags and entities, printf specifiers and {name}, {{name}},
+// %{name} and $t(key) placeholders.
+var DefaultProtected = regexp.MustCompile(`<[^<>]*>|&(#\d+|#x[0-9a-fA-F]+|\w+);|\{\{[^{}]*\}\}|%\{\w+\}|\{[^{}]*\}|\$t\([^()]*\)|` + placeholder.Printf.String())
+
+var accents = map[rune]rune{
+	'A': 'À', 'B': 'ß', 'C': 'Ç', 'D': 'Ð', 'E': 'É', 'G': 'Ĝ', 'H': 'Ĥ', 'I': 'Î', 'J': 'Ĵ', 'K': 'Ķ',
+	'L': 'Ļ', 'N': 'Ñ', 'O': 'Ö', 'R': 'Ŕ', 'S': 'Š', 'T': 'Ţ', 'U': 'Û', 'W': 'Ŵ', 'Y': 'Ý', 'Z': 'Ž',
+	'a': 'à', 'b': 'ƀ', 'c': 'ç', 'd': 'ð', 'e': 'é', 'g': 'ĝ', 'h': 'ĥ', 'i': 'î', 'j': 'ĵ', 'k': 'ķ',
+	'l': 'ļ', 'n': 'ñ', 'o': 'ö', 'r': 'ŕ', 's': 'š', 't': 'ţ', 'u': 'û', 'w': 'ŵ', 'y': 'ý', 'z': 'ž',
+}
+
+type options struct {
+	expansion float64
+	padding   string
+	prefix    string
+	suffix    string
+	accents   bool
+	protected *regexp.Regexp
+}
+
+// Option configures a Translator.
+type Option func(*options)
+
+// WithExpansion sets how much longer the output is, as a fraction of the
+// translatable characters. Default 0.3.
+func WithExpansion(fraction float64) Option {
+	return func(o *options) {
+		o.expansion = fraction
+	}
+}
+
+// WithPadding sets the string repeated to expand the text. Default "~".
+func WithPadding(padding string) Option {
+	return func(o *options) {
+		o.padding = padding
+	}
+}
+
+// WithMarkers sets the strings put around every text. Default "[" and "]".
+func WithMarkers(prefix, suffix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+		o.suffix = suffix
+	}
+}
+
+// WithoutAccents keeps letters unchanged.
+func WithoutAccents() Option {
+	return func(o *options) {
+		o.accents = false
+	}
+}
+
+// WithProtected sets the pattern of the parts that are copied unchanged.
+// Default DefaultProtected; nil protects nothing.
+func WithProtected(re *regexp.Regexp) Option {
+	return func(o *options) {
+		o.protected = re
+	}
+}
+
+// Translator pseudo-localizes text. It implements godeeplapi.Translator.
+type Translator struct {
+	opts options
+}
+
+// New creates a pseudo-localizing Translator.
+func New(opts ...Option) *Translator {
+	o := options{
+		expansion: 0.3,
+		padding:   "~",
+		prefix:    "[",
+		suffix:    "]",
+		accents:   true,
+		protected: DefaultProtected,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Translator{opts: o}
+}
+
+// Translate pseudo-localizes every text of the request. Only Text is used.
+func (t *Translator) Translate(ctx context.Context, request models.TranslationRequest) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	out := make([]string, len(request.Text))
+	for i, text := range request.Text {
+		out[i] = t.Pseudolocalize(text)
+	}
+	return out, nil
+}
+
+// Pseudolocalize returns the pseudo-localized form of text. Empty texts are
+// returned unchanged.
+func (t *Translator) Pseudolocalize(text string) string {
+	if text == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(t.opts.prefix)
+	letters := 0
+	last := 0
+	var protected [][]int
+	if t.opts.protected != nil {
+		protected = t.opts.protected.FindAllStringIndex(text, -1)
+	}
+	for _, loc := range protected {
+		letters += t.convert(&b, text[last:loc[0]])
+		b.WriteString(text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	letters += t.convert(&b, text[last:])
+
+	if t.opts.padding != "" && t.opts.expansion > 0 {
+		n := int(math.Ceil(float64(letters) * t.opts.expansion / float64(utf8.RuneCountInString(t.opts.padding))))
+		b.WriteString(strings.Repeat(t.opts.padding, n))
+	}
+	b.WriteString(t.opts.suffix)
+	return b.String()
+}
+
+// convert writes s with accented letters and returns its length in runes.
+func (t *Translator) convert(b *strings.Builder, s string) int {
+	n := 0
+	for _, r := range s {
+		n++
+		if a, ok := accents[r]; ok && t.opts.accents {
+			r = a
+		}
+		b.WriteRune(r)
+	}
+	return n
+}
@@ -0,0 +1,224 @@
+package godeeplapi
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+// Call is a method call captured by a Recorder.
+type Call struct {
+	// Method is the method name, e.g. "Translate".
+	Method string
+	// Args are the arguments after the context, e.g. the request.
+	Args []any
+	// Result is the first return value, nil for methods that only return
+	// an error.
+	Result any
+	Err    error
+}
+
+// Recorder is a Service that forwards every call to another Service, usually
+// a *Fake, and captures the calls for assertions. It is safe for concurrent
+// use.
+type Recorder struct {
+	service Service
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var (
+	_ Translator         = (*Recorder)(nil)
+	_ Improver           = (*Recorder)(nil)
+	_ GlossaryManager    = (*Recorder)(nil)
+	_ DocumentTranslator = (*Recorder)(nil)
+	_ Service            = (*Recorder)(nil)
+)
+
+// NewRecorder creates a Recorder that forwards to service.
+func NewRecorder(service Service) *Recorder {
+	return &Recorder{service: service}
+}
+
+// Calls returns the captured calls in order.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// CallsTo returns the captured calls of one method in order.
+func (r *Recorder) CallsTo(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var calls []Call
+	for _, c := range r.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Reset drops the captured calls.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+func (r *Recorder) record(method string, result any, err error, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args, Result: result, Err: err})
+}
+
+// Translate implements Translator.
+func (r *Recorder) Translate(ctx context.Context, req models.TranslationRequest) ([]string, error) {
+	result, err := r.service.Translate(ctx, req)
+	r.record("Translate", result, err, req)
+	return result, err
+}
+
+// ImproveText implements Improver.
+func (r *Recorder) ImproveText(ctx context.Context, req models.RephraseRequest) (string, error) {
+	result, err := r.service.ImproveText(ctx, req)
+	r.record("ImproveText", result, err, req)
+	return result, err
+}
+
+// TranslateFile implements DocumentTranslator.
+func (r *Recorder) TranslateFile(ctx context.Context, req models.FileTranslationRequest, targetDir string) (string, error) {
+	result, err := r.service.TranslateFile(ctx, req, targetDir)
+	r.record("TranslateFile", result, err, req, targetDir)
+	return result, err
+}
+
+// TranslateDocument implements DocumentTranslator.
+func (r *Recorder) TranslateDocument(ctx context.Context, file io.Reader, req models.FileTranslationRequest) (io.ReadCloser, *models.DocumentResult, error) {
+	body, result, err := r.service.TranslateDocument(ctx, file, req)
+	r.record("TranslateDocument", body, err, file, req)
+	return body, result, err
+}
+
+// UploadDocument implements DocumentTranslator.
+func (r *Recorder) UploadDocument(ctx context.Context, req models.FileTranslationRequest) (*models.DocumentHandle, error) {
+	result, err := r.service.UploadDocument(ctx, req)
+	r.record("UploadDocument", result, err, req)
+	return result, err
+}
+
+// GetDocumentStatus implements DocumentTranslator.
+func (r *Recorder) GetDocumentStatus(ctx context.Context, handle models.DocumentHandle) (*models.DocumentStatusResponse, error) {
+	result, err := r.service.GetDocumentStatus(ctx, handle)
+	r.record("GetDocumentStatus", result, err, handle)
+	return result, err
+}
+
+// WaitForDocument implements DocumentTranslator.
+func (r *Recorder) WaitForDocument(ctx context.Context, handle models.DocumentHandle) (*models.DocumentStatusResponse, error) {
+	result, err := r.service.WaitForDocument(ctx, handle)
+	r.record("WaitForDocument", result, err, handle)
+	return result, err
+}
+
+// ResumeDocument implements DocumentTranslator.
+func (r *Recorder) ResumeDocument(ctx context.Context, handle models.DocumentHandle, targetDir string) (string, error) {
+	result, err := r.service.ResumeDocument(ctx, handle, targetDir)
+	r.record("ResumeDocument", result, err, handle, targetDir)
+	return result, err
+}
+
+// OpenDocument implements DocumentTranslator.
+func (r *Recorder) OpenDocument(ctx context.Context, handle models.DocumentHandle) (io.ReadCloser, *models.DocumentResult, error) {
+	body, result, err := r.service.OpenDocument(ctx, handle)
+	r.record("OpenDocument", body, err, handle)
+	return body, result, err
+}
+
+// DownloadDocument implements DocumentTranslator.
+func (r *Recorder) DownloadDocument(ctx context.Context, handle models.DocumentHandle, targetDir string) (string, error) {
+	result, err := r.service.DownloadDocument(ctx, handle, targetDir)
+	r.record("DownloadDocument", result, err, handle, targetDir)
+	return result, err
+}
+
+// DownloadDocumentTo implements DocumentTranslator.
+func (r *Recorder) DownloadDocumentTo(ctx context.Context, handle models.DocumentHandle, w io.Writer) (*models.DocumentResult, error) {
+	result, err := r.service.DownloadDocumentTo(ctx, handle, w)
+	r.record("DownloadDocumentTo", result, err, handle, w)
+	return result, err
+}
+
+// DownloadDocumentBytes implements DocumentTranslator.
+func (r *Recorder) DownloadDocumentBytes(ctx context.Context, handle models.DocumentHandle) ([]byte, *models.DocumentResult, error) {
+	data, result, err := r.service.DownloadDocumentBytes(ctx, handle)
+	r.record("DownloadDocumentBytes", data, err, handle)
+	return data, result, err
+}
+
+// ListLangPairsSupportedByGlossaries implements GlossaryManager.
+func (r *Recorder) ListLangPairsSupportedByGlossaries(ctx context.Context) (*models.GlossaryListResponse, error) {
+	result, err := r.service.ListLangPairsSupportedByGlossaries(ctx)
+	r.record("ListLangPairsSupportedByGlossaries", result, err)
+	return result, err
+}
+
+// CreateGlossary implements GlossaryManager.
+func (r *Recorder) CreateGlossary(ctx context.Context, req models.CreateGlossaryRequest) (*models.CreateGlossaryResponse, error) {
+	result, err := r.service.CreateGlossary(ctx, req)
+	r.record("CreateGlossary", result, err, req)
+	return result, err
+}
+
+// ListAllGlossaries implements GlossaryManager.
+func (r *Recorder) ListAllGlossaries(ctx context.Context) (*models.AllGlossaryListResponse, error) {
+	result, err := r.service.ListAllGlossaries(ctx)
+	r.record("ListAllGlossaries", result, err)
+	return result, err
+}
+
+// GetGlossaryByID implements GlossaryManager.
+func (r *Recorder) GetGlossaryByID(ctx context.Context, id string) (*models.Glossary, error) {
+	result, err := r.service.GetGlossaryByID(ctx, id)
+	r.record("GetGlossaryByID", result, err, id)
+	return result, err
+}
+
+// EditGlossary implements GlossaryManager.
+func (r *Recorder) EditGlossary(ctx context.Context, id string, req models.EditGlossaryRequest) (*models.Glossary, error) {
+	result, err := r.service.EditGlossary(ctx, id, req)
+	r.record("EditGlossary", result, err, id, req)
+	return result, err
+}
+
+// DeleteGlossary implements GlossaryManager.
+func (r *Recorder) DeleteGlossary(ctx context.Context, id string) error {
+	err := r.service.DeleteGlossary(ctx, id)
+	r.record("DeleteGlossary", nil, err, id)
+	return err
+}
+
+// DeleteAllLangDictionaries implements GlossaryManager.
+func (r *Recorder) DeleteAllLangDictionaries(ctx context.Context, id string, query models.GlossaryLangPair) error {
+	err := r.service.DeleteAllLangDictionaries(ctx, id, query)
+	r.record("DeleteAllLangDictionaries", nil, err, id, query)
+	return err
+}
+
+// GetGlossaryEntries implements GlossaryManager.
+func (r *Recorder) GetGlossaryEntries(ctx context.Context, id string, query models.GlossaryLangPair) (*models.GlossaryEntriesResponse, error) {
+	result, err := r.service.GetGlossaryEntries(ctx, id, query)
+	r.record("GetGlossaryEntries", result, err, id, query)
+	return result, err
+}
+
+// ReplaceOrCreateDictionaryInGlossary implements GlossaryManager.
+func (r *Recorder) ReplaceOrCreateDictionaryInGlossary(ctx context.Context, id string, req models.Dictionary) (*models.EditOrCreateDictionaryInGlossaryResponse, error) {
+	result, err := r.service.ReplaceOrCreateDictionaryInGlossary(ctx, id, req)
+	r.record("ReplaceOrCreateDictionaryInGlossary", result, err, id, req)
+	return result, err
+}
@@ -2,7 +2,9 @@ package godeeplapi
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"time"
 )
@@ -13,14 +15,21 @@ func isRetryableError(err error) bool {
 		return false
 	}
 
-	if apiErr, ok := err.(*APIError); ok {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
 		// Retry on rate limiting and server errors
 		return apiErr.StatusCode == 429 || apiErr.StatusCode >= 500
 	}
 
+	// Retry on responses that were cut off
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
 	// Check for network errors
-	if netErr, ok := err.(net.Error); ok {
-		return netErr.Temporary() || netErr.Timeout()
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout()
 	}
 
 	return false
@@ -31,11 +40,14 @@ func (c *Client) retryRequest(ctx context.Context, fn func() (interface{}, error
 	var lastErr error
 
 	// Configure retry parameters
-	maxRetries := 3
-	initialBackoff := 1 * time.Second
+	maxRetries := c.maxRetries
+	initialBackoff := c.initialBackoff
+	if initialBackoff <= 0 {
+		initialBackoff = 1 * time.Second
+	}
 	maxBackoff := 30 * time.Second
 
-	for i := 0; i < maxRetries; i++ {
+	for i := 0; i <= maxRetries; i++ {
 		result, err := fn()
 		if err == nil {
 			return result, nil
@@ -44,8 +56,8 @@ func (c *Client) retryRequest(ctx context.Context, fn func() (interface{}, error
 		lastErr = err
 
 		// Check if error is retryable
-		if !isRetryableError(err) {
-			return nil, err
+		if !isRetryableError(err) || i == maxRetries {
+			break
 		}
 
 		// Check for context cancellation
@@ -72,5 +84,8 @@ func (c *Client) retryRequest(ctx context.Context, fn func() (interface{}, error
 		}
 	}
 
+	if maxRetries == 0 || !isRetryableError(lastErr) {
+		return nil, lastErr
+	}
 	return nil, fmt.Errorf("max retries exceeded: %w", lastErr)
 }
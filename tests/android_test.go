@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AdolfZahid1/godeeplapi/formats/android"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+const androidSource = `<?xml version="1.0" encoding="utf-8"?>
+<resources xmlns:xliff="urn:oasis:names:tc:xliff:document:1.2">
+    <string name="app_name" translatable="false">Demo</string>
+    <!-- Greeting on the home screen -->
+    <string name="greeting">Hello %1$s, you have <b>%2$d</b> messages</string>
+    <string name="wait">Don\'t wait for <xliff:g id="user">%s</xliff:g>\n</string>
+    <plurals name="songs">
+        <item quantity="one">%d song</item>
+        <item quantity="other">%d songs</item>
+    </plurals>
+    <string-array name="planets">
+        <item>Earth</item>
+        <item>Mars</item>
+    </string-array>
+</resources>
+`
+
+func TestAndroid_Translate(t *testing.T) {
+	source, err := android.Parse(strings.NewReader(androidSource))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := len(source.Resources); got != 5 {
+		t.Fatalf("resources: got=%d, want=5", got)
+	}
+
+	stub := &stubTranslator{}
+	got, err := android.Translate(context.Background(), stub, source, models.TranslationRequest{TargetLang: models.TargetLanguage.Polish})
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+
+	if got.Get("app_name") != nil {
+		t.Errorf("untranslatable resource should not be in the target file")
+	}
+	if v := got.Get("greeting").Value; v != "pl:Hello %1$s, you have <b>%2$d</b> messages" {
+		t.Errorf("greeting: got=%q", v)
+	}
+	if v := got.Get("wait").Value; v != `pl:Don\'t wait for <xliff:g id="user">%s</xliff:g>\n` {
+		t.Errorf("wait: got=%q", v)
+	}
+
+	var quantities []string
+	for _, item := range got.Get("songs").Items {
+		quantities = append(quantities, item.Quantity)
+	}
+	if q := strings.Join(quantities, ","); q != "one,few,many,other" {
+		t.Errorf("plural quantities: got=%q, want=%q", q, "one,few,many,other")
+	}
+	if v := got.Get("planets").Items[1].Value; v != "pl:Mars" {
+		t.Errorf("string-array item: got=%q", v)
+	}
+
+	for _, text := range stub.requests[0].Text {
+		if strings.Contains(text, "%") || strings.Contains(text, `\`) {
+			t.Errorf("placeholder or escape sent unprotected: %q", text)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := got.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if _, err := android.Parse(&buf); err != nil {
+		t.Errorf("written file cannot be parsed: %v", err)
+	}
+}
+
+func TestAndroid_ValuesDir(t *testing.T) {
+	tests := []struct {
+		lang models.Language
+		want string
+	}{
+		{lang: models.TargetLanguage.German, want: "values-de"},
+		{lang: models.TargetLanguage.PortugueseBR, want: "values-pt-rBR"},
+		{lang: models.TargetLanguage.ChineseHans, want: "values-zh-rCN"},
+		{lang: models.TargetLanguage.Indonesian, want: "values-in"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang.String(), func(t *testing.T) {
+			if got := android.ValuesDir(tt.lang); got != tt.want {
+				t.Errorf("ValuesDir() got=%q, want=%q", got, tt.want)
+			}
+		})
+	}
+}
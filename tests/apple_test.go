@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AdolfZahid1/godeeplapi/formats/apple"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+const appleStringsSource = `/* Login button */
+"login" = "Log in as %@";
+
+// Quote test
+"quote" = "Say \"hi\"\n";
+`
+
+const appleStringsDictSource = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>files</key>
+	<dict>
+		<key>NSStringLocalizedFormatKey</key>
+		<string>%#@count@ selected</string>
+		<key>count</key>
+		<dict>
+			<key>NSStringFormatSpecTypeKey</key>
+			<string>NSStringPluralRuleType</string>
+			<key>NSStringFormatValueTypeKey</key>
+			<string>d</string>
+			<key>one</key>
+			<string>%d file</string>
+			<key>other</key>
+			<string>%d files</string>
+		</dict>
+	</dict>
+</dict>
+</plist>
+`
+
+func TestApple_TranslateStrings(t *testing.T) {
+	source, err := apple.ParseStrings(strings.NewReader(appleStringsSource))
+	if err != nil {
+		t.Fatalf("ParseStrings() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := source.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), `"quote" = "Say \"hi\"\n";`) {
+		t.Errorf("escaping was not preserved:\n%s", buf.String())
+	}
+
+	stub := &stubTranslator{}
+	got, err := apple.TranslateStrings(context.Background(), stub, source, models.TranslationRequest{TargetLang: models.TargetLanguage.French})
+	if err != nil {
+		t.Fatalf("TranslateStrings() error = %v", err)
+	}
+
+	if v, _ := got.Get("login"); v != "fr:Log in as %@" {
+		t.Errorf("login: got=%q", v)
+	}
+	if v, _ := got.Get("quote"); v != "fr:Say \"hi\"\n" {
+		t.Errorf("quote: got=%q", v)
+	}
+	if len(stub.requests) != 2 || stub.requests[0].Context != "Login button" {
+		t.Errorf("comments were not sent as Context: %+v", stub.requests)
+	}
+}
+
+func TestApple_TranslateStringsDict(t *testing.T) {
+	source, err := apple.ParseStringsDict(strings.NewReader(appleStringsDictSource))
+	if err != nil {
+		t.Fatalf("ParseStringsDict() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := source.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if buf.String() != appleStringsDictSource {
+		t.Errorf("round trip mismatch:\n%s", buf.String())
+	}
+
+	got, err := apple.TranslateStringsDict(context.Background(), &stubTranslator{}, source, models.TranslationRequest{TargetLang: models.TargetLanguage.Ukrainian})
+	if err != nil {
+		t.Fatalf("TranslateStringsDict() error = %v", err)
+	}
+
+	tests := []struct {
+		path []string
+		want string
+	}{
+		{path: []string{"files", "NSStringLocalizedFormatKey"}, want: "uk:%#@count@ selected"},
+		{path: []string{"files", "count", "NSStringFormatValueTypeKey"}, want: "d"},
+		{path: []string{"files", "count", "one"}, want: "uk:%d file"},
+		{path: []string{"files", "count", "few"}, want: "uk:%d files"},
+		{path: []string{"files", "count", "many"}, want: "uk:%d files"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.path, "/"), func(t *testing.T) {
+			v, ok := got.Lookup(tt.path...)
+			if !ok || v != tt.want {
+				t.Errorf("got=%q, want=%q", v, tt.want)
+			}
+		})
+	}
+}
+
+func TestApple_LprojDir(t *testing.T) {
+	tests := []struct {
+		lang models.Language
+		want string
+	}{
+		{lang: models.TargetLanguage.PortugueseBR, want: "pt-BR.lproj"},
+		{lang: models.TargetLanguage.German, want: "de.lproj"},
+		{lang: models.TargetLanguage.ChineseSimpl, want: "zh-Hans.lproj"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang.String(), func(t *testing.T) {
+			if got := apple.LprojDir(tt.lang); got != tt.want {
+				t.Errorf("LprojDir() got=%q, want=%q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+func TestEstimateCharacters(t *testing.T) {
+	tests := []struct {
+		name string
+		req  models.TranslationRequest
+		want int64
+	}{
+		{name: "plain text", req: models.TranslationRequest{Text: []string{"Hello", "Grüße"}}, want: 10},
+		{name: "context is free", req: models.TranslationRequest{Text: []string{"Hello"}, Context: "A greeting"}, want: 5},
+		{name: "tags are not counted", req: models.TranslationRequest{Text: []string{`<p class="x">Hi <b>there</b></p>`}, TagHandling: models.TagHTML}, want: 8},
+		{name: "tags count without tag handling", req: models.TranslationRequest{Text: []string{"<b>Hi</b>"}}, want: 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := godeeplapi.EstimateCharacters(tt.req); got != tt.want {
+				t.Errorf("EstimateCharacters() got=%d, want=%d", got, tt.want)
+			}
+		})
+	}
+}
+
+func usageClient(usage string, calls *int) *godeeplapi.Client {
+	return godeeplapi.NewClient("test-key", false,
+		godeeplapi.WithHTTPClient(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			*calls++
+			return jsonResponse(http.StatusOK, usage), nil
+		})}))
+}
+
+func TestBudgetGuard(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		limits    godeeplapi.BudgetLimits
+		reserve   []int64
+		wantScope string
+	}{
+		{name: "within budgets", limits: godeeplapi.BudgetLimits{PerJob: 100, PerDay: 150, CheckQuota: true}, reserve: []int64{100, 50}},
+		{name: "per job", limits: godeeplapi.BudgetLimits{PerJob: 100}, reserve: []int64{101}, wantScope: godeeplapi.BudgetPerJob},
+		{name: "per day", limits: godeeplapi.BudgetLimits{PerDay: 150}, reserve: []int64{100, 51}, wantScope: godeeplapi.BudgetPerDay},
+		{name: "quota", limits: godeeplapi.BudgetLimits{CheckQuota: true}, reserve: []int64{400, 101}, wantScope: godeeplapi.BudgetQuota},
+		{name: "quota reserve", limits: godeeplapi.BudgetLimits{CheckQuota: true, QuotaReserve: 200}, reserve: []int64{301}, wantScope: godeeplapi.BudgetQuota},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			guard := godeeplapi.NewBudgetGuard(usageClient(`{"character_count":500,"character_limit":1000}`, &calls), tt.limits)
+
+			var err error
+			for _, chars := range tt.reserve {
+				if err = guard.Reserve(ctx, chars); err != nil {
+					break
+				}
+			}
+			if tt.wantScope == "" {
+				if err != nil {
+					t.Fatalf("Reserve() error = %v", err)
+				}
+				return
+			}
+
+			var budgetErr *godeeplapi.BudgetExceededError
+			if !errors.Is(err, godeeplapi.ErrBudgetExceeded) || !errors.As(err, &budgetErr) {
+				t.Fatalf("Reserve() error = %v, want ErrBudgetExceeded", err)
+			}
+			if budgetErr.Scope != tt.wantScope {
+				t.Errorf("Scope got=%q, want=%q", budgetErr.Scope, tt.wantScope)
+			}
+			if tt.limits.CheckQuota && calls != 1 {
+				t.Errorf("usage fetched %d times, want 1", calls)
+			}
+		})
+	}
+}
+
+func TestBudgetGuard_PauseStopsWithContext(t *testing.T) {
+	calls := 0
+	guard := godeeplapi.NewBudgetGuard(usageClient(`{}`, &calls), godeeplapi.BudgetLimits{PerDay: 10, Pause: true})
+	if err := guard.Reserve(context.Background(), 10); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := guard.Reserve(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Reserve() error = %v, want deadline exceeded while paused", err)
+	}
+	if got := guard.Spent(); got != 10 {
+		t.Errorf("Spent() got=%d, want=10", got)
+	}
+}
+
+func TestClient_TranslateWithBudget(t *testing.T) {
+	calls := 0
+	client := godeeplapi.NewClient("test-key", false,
+		godeeplapi.WithHTTPClient(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			return jsonResponse(http.StatusOK, `{"translations":[{"detected_source_language":"EN","text":"Hallo"}]}`), nil
+		})}),
+		godeeplapi.WithBudget(godeeplapi.BudgetLimits{PerDay: 8}))
+
+	req := models.TranslationRequest{Text: []string{"Hello"}, TargetLang: models.TargetLanguage.German}
+	if _, err := client.Translate(context.Background(), req); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if _, err := client.Translate(context.Background(), req); !errors.Is(err, godeeplapi.ErrBudgetExceeded) {
+		t.Errorf("second Translate() error = %v, want ErrBudgetExceeded", err)
+	}
+	if calls != 1 || client.Budget().Spent() != 5 {
+		t.Errorf("calls=%d spent=%d, want 1 call and 5 characters", calls, client.Budget().Spent())
+	}
+}
+
+func TestClient_TranslateWithBudgetReleasesFailedRequests(t *testing.T) {
+	fail := true
+	client := godeeplapi.NewClient("test-key", false,
+		godeeplapi.WithHTTPClient(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			if fail {
+				return jsonResponse(http.StatusServiceUnavailable, `{"message":"Service unavailable"}`), nil
+			}
+			return jsonResponse(http.StatusOK, `{"translations":[{"detected_source_language":"EN","text":"Hallo"}]}`), nil
+		})}),
+		godeeplapi.WithBudget(godeeplapi.BudgetLimits{PerDay: 8}))
+
+	req := models.TranslationRequest{Text: []string{"Hello"}, TargetLang: models.TargetLanguage.German}
+	for i := 0; i < 3; i++ {
+		if _, err := client.Translate(context.Background(), req); apiStatus(err) != http.StatusServiceUnavailable {
+			t.Fatalf("Translate() error = %v, want status 503", err)
+		}
+	}
+	if got := client.Budget().Spent(); got != 0 {
+		t.Errorf("Spent() after failed requests got=%d, want=0", got)
+	}
+
+	fail = false
+	if _, err := client.Translate(context.Background(), req); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if got := client.Budget().Spent(); got != 5 {
+		t.Errorf("Spent() got=%d, want=5", got)
+	}
+}
+
+func TestClient_TranslateWithBudgetJob(t *testing.T) {
+	calls := 0
+	client := godeeplapi.NewClient("test-key", false,
+		godeeplapi.WithHTTPClient(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			return jsonResponse(http.StatusOK, `{"translations":[{"detected_source_language":"EN","text":"Hallo"}]}`), nil
+		})}),
+		godeeplapi.WithBudget(godeeplapi.BudgetLimits{PerJob: 12}))
+	req := models.TranslationRequest{Text: []string{"Hello"}, TargetLang: models.TargetLanguage.German}
+
+	// Without a job every request is checked on its own.
+	for i := 0; i < 3; i++ {
+		if _, err := client.Translate(context.Background(), req); err != nil {
+			t.Fatalf("Translate() error = %v", err)
+		}
+	}
+
+	ctx, job := godeeplapi.WithBudgetJob(context.Background())
+	for i := 0; i < 2; i++ {
+		if _, err := client.Translate(ctx, req); err != nil {
+			t.Fatalf("Translate() in job error = %v", err)
+		}
+	}
+	var budgetErr *godeeplapi.BudgetExceededError
+	if _, err := client.Translate(ctx, req); !errors.As(err, &budgetErr) || budgetErr.Scope != godeeplapi.BudgetPerJob || budgetErr.Available != 2 {
+		t.Errorf("third Translate() in job error = %v, want per-job budget with 2 available", err)
+	}
+	if calls != 5 || job.Spent() != 10 {
+		t.Errorf("calls=%d job spent=%d, want 5 calls and 10 characters", calls, job.Spent())
+	}
+}
+
+func TestBudgetGuard_SharesUsageFetch(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	release := make(chan struct{})
+	client := godeeplapi.NewClient("test-key", false,
+		godeeplapi.WithHTTPClient(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			mu.Lock()
+			calls++
+			mu.Unlock()
+			<-release
+			return jsonResponse(http.StatusOK, `{"character_count":500,"character_limit":1000}`), nil
+		})}))
+	guard := godeeplapi.NewBudgetGuard(client, godeeplapi.BudgetLimits{CheckQuota: true})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- guard.Reserve(context.Background(), 50)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Reserve() error = %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("usage fetched %d times, want 1", calls)
+	}
+	var budgetErr *godeeplapi.BudgetExceededError
+	if err := guard.Reserve(context.Background(), 1); !errors.As(err, &budgetErr) || budgetErr.Available != 0 {
+		t.Errorf("Reserve() after using the quota error = %v, want quota exceeded", err)
+	}
+}
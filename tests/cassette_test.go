@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/deepltest"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+func cassetteClient(t *testing.T, baseURL, path string, mode deepltest.Mode) (*godeeplapi.Client, *deepltest.Cassette) {
+	t.Helper()
+	cassette, err := deepltest.NewCassette(path, mode, nil)
+	if err != nil {
+		t.Fatalf("NewCassette() error = %v", err)
+	}
+	client := godeeplapi.NewClient(deepltest.DefaultAuthKey, false,
+		godeeplapi.WithBaseURL(baseURL),
+		godeeplapi.WithHTTPClient(&http.Client{Transport: cassette}))
+	return client, cassette
+}
+
+func TestCassette_RecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+	ctx := context.Background()
+	req := models.TranslationRequest{Text: []string{"Hello"}, TargetLang: "DE"}
+	fileReq := func() models.FileTranslationRequest {
+		return models.FileTranslationRequest{File: strings.NewReader("Hello"), FileName: "hello.txt", TargetLang: "DE"}
+	}
+
+	srv := deepltest.NewServer()
+	baseURL := srv.URL + "/v2"
+	client, _ := cassetteClient(t, baseURL, path, deepltest.ModeRecord)
+	if _, err := client.Translate(ctx, req); err != nil {
+		t.Fatalf("Translate() while recording error = %v", err)
+	}
+	if _, err := client.TranslateFile(ctx, fileReq(), t.TempDir()); err != nil {
+		t.Fatalf("TranslateFile() while recording error = %v", err)
+	}
+	srv.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if strings.Contains(string(data), deepltest.DefaultAuthKey) {
+		t.Errorf("cassette contains the auth key")
+	}
+	if !strings.Contains(string(data), deepltest.Redacted) {
+		t.Errorf("cassette does not redact the document key")
+	}
+
+	client, cassette := cassetteClient(t, baseURL, path, deepltest.ModeReplay)
+	got, err := client.Translate(ctx, req)
+	if err != nil {
+		t.Fatalf("Translate() while replaying error = %v", err)
+	}
+	if got[0] != "de:Hello" {
+		t.Errorf("Translate() got=%q, want %q", got[0], "de:Hello")
+	}
+	dir := t.TempDir()
+	out, err := client.TranslateFile(ctx, fileReq(), dir)
+	if err != nil {
+		t.Fatalf("TranslateFile() while replaying error = %v", err)
+	}
+	if data, _ := os.ReadFile(out); string(data) != "de:Hello" {
+		t.Errorf("TranslateFile() wrote %q, want %q", data, "de:Hello")
+	}
+	if unused := cassette.Unused(); len(unused) != 0 {
+		t.Errorf("Unused() got %d interactions, want 0", len(unused))
+	}
+
+	_, err = client.Translate(ctx, models.TranslationRequest{Text: []string{"Bye"}, TargetLang: "DE"})
+	if !errors.Is(err, deepltest.ErrNoInteraction) {
+		t.Errorf("Translate() of an unrecorded text error = %v, want %v", err, deepltest.ErrNoInteraction)
+	}
+}
+
+func TestCassette_RecordMissing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+	ctx := context.Background()
+	srv := deepltest.NewServer()
+	defer srv.Close()
+
+	for _, text := range []string{"Hello", "Hello", "Bye"} {
+		client, _ := cassetteClient(t, srv.URL+"/v2", path, deepltest.ModeRecordMissing)
+		if _, err := client.Translate(ctx, models.TranslationRequest{Text: []string{text}, TargetLang: "DE"}); err != nil {
+			t.Fatalf("Translate(%q) error = %v", text, err)
+		}
+	}
+	if n := srv.Requests("/translate"); n != 2 {
+		t.Errorf("server got %d requests, want 2", n)
+	}
+}
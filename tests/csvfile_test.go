@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AdolfZahid1/godeeplapi/formats/csvfile"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+// failingTranslator fails every call after the first n.
+type failingTranslator struct {
+	stubTranslator
+	n int
+}
+
+func (f *failingTranslator) Translate(ctx context.Context, request models.TranslationRequest) ([]string, error) {
+	if len(f.requests) >= f.n {
+		return nil, errors.New("service unavailable")
+	}
+	return f.stubTranslator.Translate(ctx, request)
+}
+
+func TestCSVFile_Translate(t *testing.T) {
+	source := "sku,title,price\n1,Red shirt,10\n2,Red shirt,12\n3,,5\n"
+
+	tests := []struct {
+		name     string
+		source   string
+		targets  []models.Language
+		opts     []csvfile.Option
+		want     string
+		requests int
+	}{
+		{
+			name:     "by name, two languages",
+			source:   source,
+			targets:  []models.Language{models.TargetLanguage.German, models.TargetLanguage.French},
+			opts:     []csvfile.Option{csvfile.WithColumns("title")},
+			want:     "sku,title,price,title_DE,title_FR\n1,Red shirt,10,de:Red shirt,fr:Red shirt\n2,Red shirt,12,de:Red shirt,fr:Red shirt\n3,,5,,\n",
+			requests: 2,
+		},
+		{
+			name:     "TSV by index without header",
+			source:   "1\tBlue\n2\tGreen\n",
+			opts:     []csvfile.Option{csvfile.WithComma('\t'), csvfile.WithoutHeader(), csvfile.WithColumnIndexes(1)},
+			want:     "1\tBlue\tes:Blue\n2\tGreen\tes:Green\n",
+			requests: 1,
+		},
+		{
+			name:     "resume from checkpoint",
+			source:   source,
+			opts:     []csvfile.Option{csvfile.WithColumns("title"), csvfile.WithStartRow(2)},
+			want:     "3,,5,\n",
+			requests: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubTranslator{}
+			var out bytes.Buffer
+			_, err := csvfile.Translate(context.Background(), stub, strings.NewReader(tt.source), &out,
+				models.TranslationRequest{TargetLang: models.TargetLanguage.Spanish}, tt.targets, tt.opts...)
+			if err != nil {
+				t.Fatalf("Translate() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("output:\n%s\nwant:\n%s", out.String(), tt.want)
+			}
+			if len(stub.requests) != tt.requests {
+				t.Errorf("requests: got=%d, want=%d", len(stub.requests), tt.requests)
+			}
+		})
+	}
+}
+
+func TestCSVFile_ResumeAfterFailure(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("id,text\n")
+	for i := 0; i < 60; i++ {
+		fmt.Fprintf(&b, "%d,text %d\n", i, i)
+	}
+
+	var checkpoints []int
+	failing := &failingTranslator{n: 1}
+	var out bytes.Buffer
+	written, err := csvfile.Translate(context.Background(), failing, strings.NewReader(b.String()), &out,
+		models.TranslationRequest{TargetLang: models.TargetLanguage.German}, nil,
+		csvfile.WithColumns("text"),
+		csvfile.WithCheckpoint(func(row int) error {
+			checkpoints = append(checkpoints, row)
+			return nil
+		}))
+	if err == nil {
+		t.Fatal("expected an error from the second batch")
+	}
+	if written != 50 || len(checkpoints) != 1 || checkpoints[0] != 50 {
+		t.Fatalf("written=%d checkpoints=%v, want 50 and [50]", written, checkpoints)
+	}
+
+	if _, err := csvfile.Translate(context.Background(), &stubTranslator{}, strings.NewReader(b.String()), &out,
+		models.TranslationRequest{TargetLang: models.TargetLanguage.German}, nil,
+		csvfile.WithColumns("text"), csvfile.WithStartRow(written)); err != nil {
+		t.Fatalf("resumed Translate() error = %v", err)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != 61 {
+		t.Errorf("output lines: got=%d, want=61", lines)
+	}
+}
+
+func TestCSVFile_InvalidColumnIndexes(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		opts   []csvfile.Option
+	}{
+		{name: "negative with header", source: "sku,title\n1,Red\n", opts: []csvfile.Option{csvfile.WithColumnIndexes(-1)}},
+		{name: "out of range with header", source: "sku,title\n1,Red\n", opts: []csvfile.Option{csvfile.WithColumnIndexes(2)}},
+		{name: "negative without header", source: "1,Red\n", opts: []csvfile.Option{csvfile.WithoutHeader(), csvfile.WithColumnIndexes(-1)}},
+		{name: "out of range without header", source: "1,Red\n", opts: []csvfile.Option{csvfile.WithoutHeader(), csvfile.WithColumnIndexes(5)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			_, err := csvfile.Translate(context.Background(), &stubTranslator{}, strings.NewReader(tt.source), &out,
+				models.TranslationRequest{TargetLang: models.TargetLanguage.German}, nil, tt.opts...)
+			if err == nil || !strings.Contains(err.Error(), "out of range") {
+				t.Errorf("Translate() error = %v, want out of range", err)
+			}
+		})
+	}
+}
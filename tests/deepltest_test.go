@@ -0,0 +1,306 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/deepltest"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+func apiStatus(err error) int {
+	var apiErr *godeeplapi.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+func TestDeepltest_Translate(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	got, err := client.TranslateDetailed(context.Background(), models.TranslationRequest{
+		Text:       []string{"Hello", "World"},
+		TargetLang: "DE",
+	})
+	if err != nil {
+		t.Fatalf("TranslateDetailed() error = %v", err)
+	}
+	if got[0].Text != "de:Hello" || got[1].Text != "de:World" || got[0].DetectedSourceLanguage != "EN" {
+		t.Errorf("TranslateDetailed() got=%+v", got)
+	}
+
+	usage, err := client.GetUsageAndLimits(context.Background())
+	if err != nil {
+		t.Fatalf("GetUsageAndLimits() error = %v", err)
+	}
+	if usage.CharCount != 10 || usage.CharLimit != deepltest.DefaultCharacterLimit {
+		t.Errorf("GetUsageAndLimits() got=%+v, want 10 of %d characters", usage, deepltest.DefaultCharacterLimit)
+	}
+	if n := srv.Requests("/translate"); n != 1 {
+		t.Errorf("Requests(/translate) got=%d, want 1", n)
+	}
+}
+
+func TestDeepltest_Errors(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithCharacterLimit(8))
+	defer srv.Close()
+	ctx := context.Background()
+	req := models.TranslationRequest{Text: []string{"Hello"}, TargetLang: "DE"}
+
+	tests := []struct {
+		name   string
+		setup  func()
+		client *godeeplapi.Client
+		req    models.TranslationRequest
+		want   int
+	}{
+		{name: "invalid key", client: godeeplapi.NewClient("wrong", false, godeeplapi.WithBaseURL(srv.URL+"/v2")), req: req, want: http.StatusForbidden},
+		{name: "unsupported target", client: srv.Client(), req: models.TranslationRequest{Text: []string{"Hello"}, TargetLang: "XX"}, want: http.StatusBadRequest},
+		{name: "unknown glossary", client: srv.Client(), req: models.TranslationRequest{Text: []string{"Hello"}, SourceLang: "EN", TargetLang: "DE", GlossaryId: "missing"}, want: http.StatusNotFound},
+		{name: "quota", setup: func() { srv.SetUsage(5, 8) }, client: srv.Client(), req: req, want: 456},
+		{name: "injected", setup: func() {
+			srv.SetUsage(0, 8)
+			srv.Inject(deepltest.Fault{Path: "/translate", Status: http.StatusTooManyRequests, Times: 1})
+		}, client: srv.Client(), req: req, want: http.StatusTooManyRequests},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setup != nil {
+				tt.setup()
+			}
+			_, err := tt.client.Translate(ctx, tt.req)
+			if got := apiStatus(err); got != tt.want {
+				t.Errorf("Translate() error = %v, want status %d", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := srv.Client().Translate(ctx, req); err != nil {
+		t.Errorf("Translate() after the fault error = %v", err)
+	}
+}
+
+func TestDeepltest_Latency(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithLatency(300 * time.Millisecond))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := srv.Client().Translate(ctx, models.TranslationRequest{Text: []string{"Hello"}, TargetLang: "DE"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Translate() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestDeepltest_LanguagesAndImprove(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+	client := srv.Client(godeeplapi.WithLanguageRegistry(0))
+
+	ok, err := client.Languages().SupportsFormality(ctx, "DE")
+	if err != nil || !ok {
+		t.Errorf("SupportsFormality(DE) got=%v, err=%v, want true", ok, err)
+	}
+	if ok, _ := client.Languages().IsValidTarget(ctx, "EN-GB"); !ok {
+		t.Errorf("IsValidTarget(EN-GB) got=false, want true")
+	}
+
+	improved, err := client.ImproveText(ctx, models.RephraseRequest{Text: []string{"this  is   fine"}, TargetLanguage: "en-GB"})
+	if err != nil {
+		t.Fatalf("ImproveText() error = %v", err)
+	}
+	if improved != "This is fine" {
+		t.Errorf("ImproveText() got=%q, want %q", improved, "This is fine")
+	}
+}
+
+func TestDeepltest_Glossaries(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+	v3 := srv.ClientV3()
+
+	created, err := v3.CreateGlossary(ctx, models.CreateGlossaryRequest{
+		Name: "Shop",
+		Dictionaries: []models.Dictionary{{
+			SourceLanguage: "EN",
+			TargetLanguage: "DE",
+			Entries:        "cart,Warenkorb\ncheckout,Kasse",
+			EntriesFormat:  "csv",
+		}},
+	})
+	if err != nil {
+		t.Fatalf("CreateGlossary() error = %v", err)
+	}
+
+	translated, err := srv.Client().Translate(ctx, models.TranslationRequest{
+		Text:       []string{"cart", "basket"},
+		SourceLang: "EN",
+		TargetLang: "DE",
+		GlossaryId: created.GlossaryID,
+	})
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if translated[0] != "Warenkorb" || translated[1] != "de:basket" {
+		t.Errorf("Translate() got=%q", translated)
+	}
+
+	entries, err := v3.GetGlossaryEntries(ctx, created.GlossaryID, models.GlossaryLangPair{SourceLanguage: "EN", TargetLanguage: "DE"})
+	if err != nil {
+		t.Fatalf("GetGlossaryEntries() error = %v", err)
+	}
+	if got := entries.Dictionaries[0].Entries; got != "cart\tWarenkorb\ncheckout\tKasse" {
+		t.Errorf("GetGlossaryEntries() got=%q", got)
+	}
+
+	if _, err := v3.ReplaceOrCreateDictionaryInGlossary(ctx, created.GlossaryID, models.Dictionary{
+		SourceLanguage: "EN",
+		TargetLanguage: "FR",
+		Entries:        "cart\tpanier\ncart\tchariot",
+		EntriesFormat:  "tsv",
+	}); apiStatus(err) != http.StatusBadRequest {
+		t.Errorf("ReplaceOrCreateDictionaryInGlossary() with duplicate entries error = %v, want status 400", err)
+	}
+
+	if err := v3.DeleteGlossary(ctx, created.GlossaryID); err != nil {
+		t.Fatalf("DeleteGlossary() error = %v", err)
+	}
+	if _, err := v3.GetGlossaryByID(ctx, created.GlossaryID); apiStatus(err) != http.StatusNotFound {
+		t.Errorf("GetGlossaryByID() error = %v, want status 404", err)
+	}
+}
+
+func TestDeepltest_TranslateFile(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+	dir := t.TempDir()
+
+	path, err := srv.Client().TranslateFile(context.Background(), models.FileTranslationRequest{
+		File:       strings.NewReader("Hello"),
+		FileName:   "hello.txt",
+		TargetLang: "DE",
+	}, dir)
+	if err != nil {
+		t.Fatalf("TranslateFile() error = %v", err)
+	}
+	if path != filepath.Join(dir, "hello.txt") {
+		t.Errorf("TranslateFile() path=%q", path)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "de:Hello" {
+		t.Errorf("TranslateFile() wrote %q, want %q", data, "de:Hello")
+	}
+
+	srv.FailDocuments("Unsupported content")
+	_, err = srv.Client().TranslateFile(context.Background(), models.FileTranslationRequest{
+		File:       strings.NewReader("Hello"),
+		FileName:   "hello.txt",
+		TargetLang: "DE",
+	}, dir)
+	if err == nil || !strings.Contains(err.Error(), "Unsupported content") {
+		t.Errorf("TranslateFile() error = %v, want the document error", err)
+	}
+}
+
+func TestDeepltest_RephraseWireFormat(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "improvements",
+			body:       `{"text":["hello  there","fine"],"target_lang":"de"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"improvements":[{"text":"Hello there","target_language":"de","detected_source_language":"de"},{"text":"Fine","target_language":"de","detected_source_language":"de"}]}`,
+		},
+		{name: "no text", body: `{"text":[]}`, wantStatus: http.StatusBadRequest},
+		{name: "unsupported language", body: `{"text":["hi"],"target_lang":"ja"}`, wantStatus: http.StatusBadRequest},
+		{name: "unknown tone", body: `{"text":["hi"],"tone":"angry"}`, wantStatus: http.StatusBadRequest},
+		{name: "style and tone", body: `{"text":["hi"],"writing_style":"business","tone":"friendly"}`, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := postJSON(t, srv, "/v2/write/rephrase", tt.body)
+			if status != tt.wantStatus {
+				t.Fatalf("status got=%d, want=%d: %s", status, tt.wantStatus, body)
+			}
+			if tt.wantBody != "" && strings.TrimSpace(body) != tt.wantBody {
+				t.Errorf("body got=%s, want=%s", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+// postJSON sends a raw JSON body to the server, so that the server's checks
+// are tested without the client's validation.
+func postJSON(t *testing.T, srv *deepltest.Server, path, body string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+deepltest.DefaultAuthKey)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(data)
+}
+
+func TestDeepltest_ChecksRequests(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+		wantMsg    string
+	}{
+		{name: "lower-case languages", path: "/v2/translate", body: `{"text":["Hi"],"source_lang":"en","target_lang":"de"}`, wantStatus: http.StatusOK},
+		{name: "no target", path: "/v2/translate", body: `{"text":["Hi"]}`, wantStatus: http.StatusBadRequest, wantMsg: "'target_lang' not specified"},
+		{name: "unknown source", path: "/v2/translate", body: `{"text":["Hi"],"source_lang":"XX","target_lang":"DE"}`, wantStatus: http.StatusBadRequest, wantMsg: "'source_lang' not supported"},
+		{name: "formality", path: "/v2/translate", body: `{"text":["Hi"],"target_lang":"DE","formality":"less"}`, wantStatus: http.StatusOK},
+		{name: "formality not supported", path: "/v2/translate", body: `{"text":["Hi"],"target_lang":"EN-US","formality":"more"}`, wantStatus: http.StatusBadRequest, wantMsg: "'formality' is not supported"},
+		{name: "preferred formality", path: "/v2/translate", body: `{"text":["Hi"],"target_lang":"EN-US","formality":"prefer_more"}`, wantStatus: http.StatusOK},
+		{name: "unknown formality", path: "/v2/translate", body: `{"text":["Hi"],"target_lang":"DE","formality":"polite"}`, wantStatus: http.StatusBadRequest, wantMsg: "'formality' not supported"},
+		{name: "unknown tag handling", path: "/v2/translate", body: `{"text":["Hi"],"target_lang":"DE","tag_handling":"markdown"}`, wantStatus: http.StatusBadRequest, wantMsg: "'tag_handling' not supported"},
+		{name: "tags without tag handling", path: "/v2/translate", body: `{"text":["Hi"],"target_lang":"DE","ignore_tags":["x"]}`, wantStatus: http.StatusBadRequest, wantMsg: "'ignore_tags' requires 'tag_handling'"},
+		{name: "glossary without source", path: "/v2/translate", body: `{"text":["Hi"],"target_lang":"DE","glossary_id":"abc"}`, wantStatus: http.StatusBadRequest, wantMsg: "'source_lang' is required"},
+		{name: "glossary without name", path: "/v3/glossaries", body: `{"dictionaries":[{"source_lang":"en","target_lang":"de","entries":"a\tb","entries_format":"tsv"}]}`, wantStatus: http.StatusBadRequest, wantMsg: "'name' not specified"},
+		{name: "glossary with same languages", path: "/v3/glossaries", body: `{"name":"g","dictionaries":[{"source_lang":"en","target_lang":"EN-GB","entries":"a\tb"}]}`, wantStatus: http.StatusBadRequest, wantMsg: "must differ"},
+		{name: "glossary entries format", path: "/v3/glossaries", body: `{"name":"g","dictionaries":[{"source_lang":"en","target_lang":"de","entries":"a\tb","entries_format":"xlsx"}]}`, wantStatus: http.StatusBadRequest, wantMsg: "'entries_format' not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := postJSON(t, srv, tt.path, tt.body)
+			if status != tt.wantStatus {
+				t.Fatalf("status got=%d, want=%d: %s", status, tt.wantStatus, body)
+			}
+			if !strings.Contains(body, tt.wantMsg) {
+				t.Errorf("body got=%s, want message containing %q", body, tt.wantMsg)
+			}
+		})
+	}
+}
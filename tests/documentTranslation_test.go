@@ -0,0 +1,545 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/deepltest"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+func TestClient_ResumeDocument(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithDocumentPolls(0, 1))
+	defer srv.Close()
+	ctx := context.Background()
+
+	handle, err := srv.Client().UploadDocument(ctx, models.FileTranslationRequest{
+		File:       strings.NewReader("Hello"),
+		FileName:   "docs/hello.txt",
+		TargetLang: "EN",
+	})
+	if err != nil {
+		t.Fatalf("UploadDocument() error = %v", err)
+	}
+	if handle.DocumentId == "" || handle.DocumentKey == "" || handle.TargetLang != "EN-US" {
+		t.Errorf("UploadDocument() got=%+v", handle)
+	}
+
+	// Persist the handle and continue with a new client, as after a restart.
+	data, err := json.Marshal(handle)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var restored models.DocumentHandle
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	client := srv.Client()
+
+	status, err := client.GetDocumentStatus(ctx, restored)
+	if err != nil {
+		t.Fatalf("GetDocumentStatus() error = %v", err)
+	}
+	if status.DocumentStatus != models.DocumentStatusTranslating {
+		t.Errorf("GetDocumentStatus() got=%q, want %q", status.DocumentStatus, models.DocumentStatusTranslating)
+	}
+
+	dir := t.TempDir()
+	path, err := client.ResumeDocument(ctx, restored, dir)
+	if err != nil {
+		t.Fatalf("ResumeDocument() error = %v", err)
+	}
+	if path != filepath.Join(dir, "hello.txt") {
+		t.Errorf("ResumeDocument() path=%q", path)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "en-us:Hello" {
+		t.Errorf("ResumeDocument() wrote %q, want %q", data, "en-us:Hello")
+	}
+
+	if _, err := client.DownloadDocument(ctx, restored, dir); apiStatus(err) != http.StatusNotFound {
+		t.Errorf("DownloadDocument() of a downloaded document error = %v, want status 404", err)
+	}
+}
+
+func TestClient_WaitForDocumentError(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+	srv.FailDocuments("Source and target language are equal")
+	client := srv.Client()
+
+	handle, err := client.UploadDocument(context.Background(), models.FileTranslationRequest{
+		File:       strings.NewReader("Hallo"),
+		FileName:   "hallo.txt",
+		TargetLang: "DE",
+	})
+	if err != nil {
+		t.Fatalf("UploadDocument() error = %v", err)
+	}
+	_, err = client.WaitForDocument(context.Background(), *handle)
+	if err == nil || !strings.Contains(err.Error(), "Source and target language are equal") {
+		t.Errorf("WaitForDocument() error = %v, want the document error", err)
+	}
+}
+
+func TestClient_GetDocumentStatusWrongKey(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	handle, err := client.UploadDocument(context.Background(), models.FileTranslationRequest{
+		File:       strings.NewReader("Hello"),
+		FileName:   "hello.txt",
+		TargetLang: "DE",
+	})
+	if err != nil {
+		t.Fatalf("UploadDocument() error = %v", err)
+	}
+	handle.DocumentKey = "wrong"
+	if _, err := client.GetDocumentStatus(context.Background(), *handle); apiStatus(err) != http.StatusForbidden {
+		t.Errorf("GetDocumentStatus() error = %v, want status 403", err)
+	}
+}
+
+func TestClient_TranslateDocumentStream(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	body, result, err := client.TranslateDocument(ctx, strings.NewReader("Hello"), models.FileTranslationRequest{
+		FileName:     "hello.txt",
+		TargetLang:   "DE",
+		OutputFormat: "md",
+	})
+	if err != nil {
+		t.Fatalf("TranslateDocument() error = %v", err)
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if string(data) != "de:Hello" {
+		t.Errorf("TranslateDocument() got=%q, want %q", data, "de:Hello")
+	}
+	if result.FileName != "hello.md" || result.ContentType != "application/octet-stream" {
+		t.Errorf("TranslateDocument() result=%+v", result)
+	}
+
+	upload := func() models.DocumentHandle {
+		handle, err := client.UploadDocument(ctx, models.FileTranslationRequest{File: strings.NewReader("Bye"), FileName: "bye.txt", TargetLang: "FR"})
+		if err != nil {
+			t.Fatalf("UploadDocument() error = %v", err)
+		}
+		if _, err := client.WaitForDocument(ctx, *handle); err != nil {
+			t.Fatalf("WaitForDocument() error = %v", err)
+		}
+		return *handle
+	}
+
+	var buf bytes.Buffer
+	result, err = client.DownloadDocumentTo(ctx, upload(), &buf)
+	if err != nil {
+		t.Fatalf("DownloadDocumentTo() error = %v", err)
+	}
+	if buf.String() != "fr:Bye" || result.Size != 6 || result.FileName != "bye.txt" {
+		t.Errorf("DownloadDocumentTo() got=%q, result=%+v", buf.String(), result)
+	}
+
+	data, result, err = client.DownloadDocumentBytes(ctx, upload())
+	if err != nil {
+		t.Fatalf("DownloadDocumentBytes() error = %v", err)
+	}
+	if string(data) != "fr:Bye" || result.FileName != "bye.txt" {
+		t.Errorf("DownloadDocumentBytes() got=%q, result=%+v", data, result)
+	}
+}
+
+func TestClient_UploadDocumentStreaming(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+
+	var contentLength int64
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path == "/v2/document" {
+			contentLength = r.ContentLength
+		}
+		return http.DefaultTransport.RoundTrip(r)
+	})
+	client := srv.Client(godeeplapi.WithHTTPClient(&http.Client{Transport: transport}))
+
+	content := strings.Repeat("Hello world. ", 1000)
+	var sent, total int64
+	if _, err := client.UploadDocument(ctx, models.FileTranslationRequest{
+		File:       strings.NewReader(content),
+		FileName:   "hello.txt",
+		TargetLang: "DE",
+		OnProgress: func(p models.DocumentProgress) {
+			if p.Stage == models.DocumentStageUploading {
+				sent, total = p.Bytes, p.Total
+			}
+		},
+	}); err != nil {
+		t.Fatalf("UploadDocument() error = %v", err)
+	}
+	if contentLength <= int64(len(content)) {
+		t.Errorf("Content-Length got=%d, want more than the file size %d", contentLength, len(content))
+	}
+	if sent != int64(len(content)) || total != int64(len(content)) {
+		t.Errorf("progress got=%d/%d, want %d/%d", sent, total, len(content), len(content))
+	}
+
+	// A reader of unknown size is sent without a Content-Length.
+	if _, err := client.UploadDocument(ctx, models.FileTranslationRequest{
+		File:       io.MultiReader(strings.NewReader(content)),
+		FileName:   "hello.txt",
+		TargetLang: "DE",
+	}); err != nil {
+		t.Fatalf("UploadDocument() of unknown size error = %v", err)
+	}
+	if contentLength > 0 {
+		t.Errorf("Content-Length of unknown size got=%d, want none", contentLength)
+	}
+}
+
+func TestClient_UploadDocumentTooLarge(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	content := strings.Repeat("1\n00:00:01,000 --> 00:00:02,000\nHello\n\n", 5000)
+
+	tests := []struct {
+		name string
+		file io.Reader
+	}{
+		{name: "known size", file: strings.NewReader(content)},
+		{name: "unknown size", file: io.MultiReader(strings.NewReader(content))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.UploadDocument(context.Background(), models.FileTranslationRequest{
+				File:       tt.file,
+				FileName:   "movie.srt",
+				TargetLang: "DE",
+			})
+			if !errors.Is(err, godeeplapi.ErrDocumentTooLarge) {
+				t.Errorf("UploadDocument() error = %v, want %v", err, godeeplapi.ErrDocumentTooLarge)
+			}
+		})
+	}
+	if n := srv.Requests("/document"); n > 1 {
+		t.Errorf("server got %d uploads, want at most the streamed one", n)
+	}
+}
+
+func TestClient_TranslateFileProgress(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithDocumentPolls(0, 1))
+	defer srv.Close()
+
+	var stages []models.DocumentStage
+	var last models.DocumentProgress
+	_, err := srv.Client().TranslateFile(context.Background(), models.FileTranslationRequest{
+		File:       strings.NewReader("Hello"),
+		FileName:   "hello.txt",
+		TargetLang: "DE",
+		OnProgress: func(p models.DocumentProgress) {
+			if len(stages) == 0 || stages[len(stages)-1] != p.Stage {
+				stages = append(stages, p.Stage)
+			}
+			if p.Stage == models.DocumentStageUploading && (p.Bytes != 5 || p.Total != 5) {
+				t.Errorf("upload progress got=%+v, want 5 of 5 bytes", p)
+			}
+			if p.Stage == models.DocumentStageDone && p.BilledCharacters != 5 {
+				t.Errorf("done progress got=%+v, want 5 billed characters", p)
+			}
+			last = p
+		},
+	}, t.TempDir())
+	if err != nil {
+		t.Fatalf("TranslateFile() error = %v", err)
+	}
+
+	want := []models.DocumentStage{
+		models.DocumentStageUploading,
+		models.DocumentStageTranslating,
+		models.DocumentStageDone,
+		models.DocumentStageDownloading,
+		models.DocumentStageDownloaded,
+	}
+	if len(stages) != len(want) {
+		t.Fatalf("stages got=%v, want %v", stages, want)
+	}
+	for i := range want {
+		if stages[i] != want[i] {
+			t.Errorf("stages got=%v, want %v", stages, want)
+			break
+		}
+	}
+	if last.DocumentId == "" || last.Bytes != 8 || last.Total != 8 {
+		t.Errorf("last progress got=%+v, want 8 of 8 bytes downloaded", last)
+	}
+}
+
+func TestClient_WaitForDocumentPollStrategy(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithDocumentPolls(3, 2))
+	defer srv.Close()
+	client := srv.Client(godeeplapi.WithPollStrategy(godeeplapi.PollStrategy{
+		MinInterval: time.Millisecond,
+		MaxInterval: 5 * time.Millisecond,
+		Backoff:     2,
+	}))
+
+	handle, err := client.UploadDocument(context.Background(), models.FileTranslationRequest{
+		File:       strings.NewReader("Hello"),
+		FileName:   "hello.txt",
+		TargetLang: "DE",
+	})
+	if err != nil {
+		t.Fatalf("UploadDocument() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	status, err := client.WaitForDocument(ctx, *handle)
+	if err != nil {
+		t.Fatalf("WaitForDocument() error = %v", err)
+	}
+	if status.DocumentStatus != models.DocumentStatusDone {
+		t.Errorf("WaitForDocument() got=%q, want %q", status.DocumentStatus, models.DocumentStatusDone)
+	}
+	if got := srv.Requests("/document/" + handle.DocumentId); got != 6 {
+		t.Errorf("WaitForDocument() checked the status %d times, want 6", got)
+	}
+}
+
+func TestClient_WaitForDocumentStuck(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithDocumentPolls(1000, 0))
+	defer srv.Close()
+	client := srv.Client(godeeplapi.WithPollStrategy(godeeplapi.PollStrategy{
+		MinInterval: time.Millisecond,
+		MaxInterval: 2 * time.Millisecond,
+		MaxQueued:   20 * time.Millisecond,
+	}))
+
+	handle, err := client.UploadDocument(context.Background(), models.FileTranslationRequest{
+		File:       strings.NewReader("Hello"),
+		FileName:   "hello.txt",
+		TargetLang: "DE",
+	})
+	if err != nil {
+		t.Fatalf("UploadDocument() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.WaitForDocument(ctx, *handle); !errors.Is(err, godeeplapi.ErrDocumentStuck) {
+		t.Errorf("WaitForDocument() error = %v, want ErrDocumentStuck", err)
+	}
+}
+
+func TestClient_TranslateFileDeadline(t *testing.T) {
+	srv := deepltest.NewServer(deepltest.WithDocumentPolls(1000, 0))
+	defer srv.Close()
+	client := srv.Client(godeeplapi.WithPollStrategy(godeeplapi.PollStrategy{
+		MinInterval: time.Millisecond,
+		MaxInterval: 2 * time.Millisecond,
+		MaxQueued:   -1,
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.TranslateFile(ctx, models.FileTranslationRequest{
+		File:       strings.NewReader("Hello"),
+		FileName:   "hello.txt",
+		TargetLang: "DE",
+	}, t.TempDir())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("TranslateFile() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+// failingReader returns some data and then an error, like a broken download.
+type failingReader struct{ sent bool }
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.sent {
+		return 0, io.ErrUnexpectedEOF
+	}
+	r.sent = true
+	return copy(p, "partial"), nil
+}
+
+func TestClient_DownloadDocumentSafePath(t *testing.T) {
+	tests := []struct {
+		name        string
+		disposition string
+		body        io.Reader
+		want        string
+		wantErr     bool
+	}{
+		{name: "plain", disposition: `attachment; filename="hello.txt"`, body: strings.NewReader("ok"), want: "hello.txt"},
+		{name: "traversal", disposition: `attachment; filename="../../evil.txt"`, body: strings.NewReader("ok"), want: "evil.txt"},
+		{name: "windows traversal", disposition: `attachment; filename="..\\..\\evil.txt"`, body: strings.NewReader("ok"), want: "evil.txt"},
+		{name: "dots only", disposition: `attachment; filename=".."`, body: strings.NewReader("ok"), want: "translated_document"},
+		{name: "reserved characters", disposition: `attachment; filename="a<b>:c?.txt"`, body: strings.NewReader("ok"), want: "abc.txt"},
+		{name: "reserved device", disposition: `attachment; filename="CON"`, body: strings.NewReader("ok"), want: "_CON"},
+		{name: "reserved device with extension", disposition: `attachment; filename="nul.txt"`, body: strings.NewReader("ok"), want: "_nul.txt"},
+		{name: "reserved device with extensions", disposition: `attachment; filename="Com1.tar.gz"`, body: strings.NewReader("ok"), want: "_Com1.tar.gz"},
+		{name: "reserved device with space", disposition: `attachment; filename="lpt9 .docx"`, body: strings.NewReader("ok"), want: "_lpt9 .docx"},
+		{name: "device prefix", disposition: `attachment; filename="console.txt"`, body: strings.NewReader("ok"), want: "console.txt"},
+		{name: "broken download", disposition: `attachment; filename="hello.txt"`, body: &failingReader{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := godeeplapi.NewClient("key", false, godeeplapi.WithHTTPClient(&http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Header:     http.Header{"Content-Disposition": []string{tt.disposition}},
+						Body:       io.NopCloser(tt.body),
+					}, nil
+				}),
+			}))
+
+			parent := t.TempDir()
+			dir := filepath.Join(parent, "out")
+			path, err := client.DownloadDocument(context.Background(), models.DocumentHandle{DocumentId: "1", DocumentKey: "k"}, dir)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("DownloadDocument() path=%q, want an error", path)
+				}
+				if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+					t.Errorf("DownloadDocument() left %d files behind", len(entries))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DownloadDocument() error = %v", err)
+			}
+			if path != filepath.Join(dir, tt.want) {
+				t.Errorf("DownloadDocument() path=%q, want %q", path, filepath.Join(dir, tt.want))
+			}
+			if entries, _ := os.ReadDir(parent); len(entries) != 1 {
+				t.Errorf("DownloadDocument() wrote outside the target directory: %v", entries)
+			}
+		})
+	}
+}
+
+func TestClient_DownloadDocumentCollision(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		collision godeeplapi.CollisionPolicy
+		want      string
+		wantFiles int
+		wantErr   error
+	}{
+		{name: "overwrite", collision: godeeplapi.CollisionOverwrite, want: "hello.de.txt", wantFiles: 2},
+		{name: "suffix", collision: godeeplapi.CollisionSuffix, want: "hello.de (2).txt", wantFiles: 3},
+		{name: "fail", collision: godeeplapi.CollisionFail, wantErr: godeeplapi.ErrOutputExists},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := srv.Client(godeeplapi.WithOutputOptions(godeeplapi.OutputOptions{
+				Template:  "{name}.{target}.{ext}",
+				Collision: tt.collision,
+			}))
+			dir := t.TempDir()
+			for _, name := range []string{"hello.de.txt", "hello.de (1).txt"} {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte("old"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			handle, err := client.UploadDocument(ctx, models.FileTranslationRequest{
+				File:       strings.NewReader("Hello"),
+				FileName:   "hello.txt",
+				TargetLang: "DE",
+			})
+			if err != nil {
+				t.Fatalf("UploadDocument() error = %v", err)
+			}
+			path, err := client.ResumeDocument(ctx, *handle, dir)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ResumeDocument() error = %v, want %v", err, tt.wantErr)
+				}
+				if data, _ := os.ReadFile(filepath.Join(dir, "hello.de.txt")); string(data) != "old" {
+					t.Errorf("ResumeDocument() replaced the existing file with %q", data)
+				}
+				// The document is still available at DeepL.
+				if _, _, err := client.DownloadDocumentBytes(ctx, *handle); err != nil {
+					t.Errorf("DownloadDocumentBytes() error = %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResumeDocument() error = %v", err)
+			}
+			if path != filepath.Join(dir, tt.want) {
+				t.Errorf("ResumeDocument() path=%q, want %q", path, filepath.Join(dir, tt.want))
+			}
+			if data, _ := os.ReadFile(path); string(data) != "de:Hello" {
+				t.Errorf("ResumeDocument() wrote %q, want %q", data, "de:Hello")
+			}
+			if entries, _ := os.ReadDir(dir); len(entries) != tt.wantFiles {
+				t.Errorf("ResumeDocument() left %d files, want %d", len(entries), tt.wantFiles)
+			}
+		})
+	}
+}
+
+func TestClient_DownloadDocumentCollisionOutputFormat(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+	client := srv.Client(godeeplapi.WithOutputOptions(godeeplapi.OutputOptions{Collision: godeeplapi.CollisionFail}))
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.html"), []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	handle, err := client.UploadDocument(ctx, models.FileTranslationRequest{
+		File:         strings.NewReader("Hello"),
+		FileName:     "a.txt",
+		TargetLang:   "DE",
+		OutputFormat: "html",
+	})
+	if err != nil {
+		t.Fatalf("UploadDocument() error = %v", err)
+	}
+	if got := handle.ResultFileName(); got != "a.html" {
+		t.Errorf("ResultFileName() got=%q, want %q", got, "a.html")
+	}
+	if _, err := client.ResumeDocument(ctx, *handle, dir); !errors.Is(err, godeeplapi.ErrOutputExists) {
+		t.Fatalf("ResumeDocument() error = %v, want ErrOutputExists", err)
+	}
+
+	// The document was not downloaded and can be saved elsewhere.
+	path, err := client.DownloadDocument(ctx, *handle, filepath.Join(dir, "new"))
+	if err != nil {
+		t.Fatalf("DownloadDocument() error = %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "de:Hello" {
+		t.Errorf("DownloadDocument() wrote %q, want %q", data, "de:Hello")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("DownloadDocument() left %d entries in the first directory, want 2", len(entries))
+	}
+}
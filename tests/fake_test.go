@@ -0,0 +1,233 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/formats/jsonlocale"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+func TestFake_Translate(t *testing.T) {
+	fake := godeeplapi.NewFake(map[string]string{"Hello": "Hallo"})
+	got, err := fake.Translate(context.Background(), models.TranslationRequest{
+		Text:       []string{"Hello", "World"},
+		TargetLang: models.TargetLanguage.German,
+	})
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if want := []string{"Hallo", "de:World"}; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Translate() got=%q, want=%q", got, want)
+	}
+
+	_, err = fake.Translate(context.Background(), models.TranslationRequest{Text: []string{"Hello"}})
+	checkValidation(t, err, []string{"TargetLang"})
+
+	fake.Err = godeeplapi.ErrQuotaExceeded
+	if _, err := fake.Translate(context.Background(), models.TranslationRequest{Text: []string{"Hello"}, TargetLang: "DE"}); !errors.Is(err, godeeplapi.ErrQuotaExceeded) {
+		t.Errorf("Translate() error = %v, want %v", err, godeeplapi.ErrQuotaExceeded)
+	}
+}
+
+func TestFake_WithFormats(t *testing.T) {
+	fake := godeeplapi.NewFake(nil)
+	source, err := jsonlocale.Parse(strings.NewReader(`{"title": "Hello"}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	got, err := jsonlocale.Translate(context.Background(), fake, source, models.TranslationRequest{TargetLang: "FR"})
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if v := got.Get("title"); v == nil || v.Str != "fr:Hello" {
+		t.Errorf("title got=%+v, want=%q", v, "fr:Hello")
+	}
+}
+
+func TestFake_TranslateFile(t *testing.T) {
+	dir := t.TempDir()
+	fake := godeeplapi.NewFake(nil)
+	path, err := fake.TranslateFile(context.Background(), models.FileTranslationRequest{
+		File:       strings.NewReader("Hello"),
+		FileName:   "hello.txt",
+		TargetLang: "DE",
+	}, dir)
+	if err != nil {
+		t.Fatalf("TranslateFile() error = %v", err)
+	}
+	if path != filepath.Join(dir, "hello.txt") {
+		t.Errorf("TranslateFile() path=%q", path)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "de:Hello" {
+		t.Errorf("TranslateFile() wrote %q, want %q", data, "de:Hello")
+	}
+}
+
+func TestFake_DocumentLifecycle(t *testing.T) {
+	ctx := context.Background()
+	fake := godeeplapi.NewFake(map[string]string{"Hello": "Hallo"})
+	rec := godeeplapi.NewRecorder(fake)
+	var service godeeplapi.DocumentTranslator = rec
+
+	handle, err := service.UploadDocument(ctx, models.FileTranslationRequest{
+		File:         strings.NewReader("Hello"),
+		FileName:     "hello.txt",
+		OutputFormat: "md",
+		TargetLang:   "DE",
+	})
+	if err != nil {
+		t.Fatalf("UploadDocument() error = %v", err)
+	}
+	status, err := service.WaitForDocument(ctx, *handle)
+	if err != nil || status.DocumentStatus != models.DocumentStatusDone || status.BilledChars != 5 {
+		t.Fatalf("WaitForDocument() got=%+v, err=%v, want done with 5 characters", status, err)
+	}
+
+	data, result, err := service.DownloadDocumentBytes(ctx, *handle)
+	if err != nil {
+		t.Fatalf("DownloadDocumentBytes() error = %v", err)
+	}
+	if string(data) != "Hallo" || result.FileName != "hello.md" {
+		t.Errorf("DownloadDocumentBytes() got=%q %+v, want %q in hello.md", data, result, "Hallo")
+	}
+	if _, err := service.GetDocumentStatus(ctx, *handle); !errors.Is(err, godeeplapi.ErrNotFound) {
+		t.Errorf("GetDocumentStatus() after download error = %v, want %v", err, godeeplapi.ErrNotFound)
+	}
+
+	body, _, err := service.TranslateDocument(ctx, strings.NewReader("World"), models.FileTranslationRequest{TargetLang: "FR"})
+	if err != nil {
+		t.Fatalf("TranslateDocument() error = %v", err)
+	}
+	defer body.Close()
+	if data, _ := io.ReadAll(body); string(data) != "fr:World" {
+		t.Errorf("TranslateDocument() got=%q, want %q", data, "fr:World")
+	}
+
+	var methods []string
+	for _, c := range rec.Calls() {
+		methods = append(methods, c.Method)
+	}
+	if got, want := strings.Join(methods, ","), "UploadDocument,WaitForDocument,DownloadDocumentBytes,GetDocumentStatus,TranslateDocument"; got != want {
+		t.Errorf("recorded calls got=%s, want=%s", got, want)
+	}
+}
+
+func TestFake_GlossaryLifecycle(t *testing.T) {
+	ctx := context.Background()
+	fake := godeeplapi.NewFake(nil)
+
+	created, err := fake.CreateGlossary(ctx, models.CreateGlossaryRequest{
+		Name: "Product",
+		Dictionaries: []models.Dictionary{{
+			SourceLanguage: "EN",
+			TargetLanguage: "DE",
+			Entries:        "cart\tWarenkorb\ncheckout\tKasse",
+			EntriesFormat:  "tsv",
+		}},
+	})
+	if err != nil {
+		t.Fatalf("CreateGlossary() error = %v", err)
+	}
+	id := created.GlossaryID
+	if id == "" || created.Dictionaries[0].Entries != "" {
+		t.Fatalf("CreateGlossary() got=%+v, want an ID and no entries", created.Glossary)
+	}
+
+	pair := models.GlossaryLangPair{SourceLanguage: "EN", TargetLanguage: "DE"}
+	entries, err := fake.GetGlossaryEntries(ctx, id, pair)
+	if err != nil {
+		t.Fatalf("GetGlossaryEntries() error = %v", err)
+	}
+	if got := entries.Dictionaries[0].Entries; got != "cart\tWarenkorb\ncheckout\tKasse" {
+		t.Errorf("GetGlossaryEntries() got=%q", got)
+	}
+
+	resp, err := fake.ReplaceOrCreateDictionaryInGlossary(ctx, id, models.Dictionary{
+		SourceLanguage: "EN",
+		TargetLanguage: "FR",
+		Entries:        "cart\tpanier",
+		EntriesFormat:  "tsv",
+	})
+	if err != nil {
+		t.Fatalf("ReplaceOrCreateDictionaryInGlossary() error = %v", err)
+	}
+	if resp.EntryCount != 1 {
+		t.Errorf("ReplaceOrCreateDictionaryInGlossary() EntryCount=%d, want 1", resp.EntryCount)
+	}
+
+	edited, err := fake.EditGlossary(ctx, id, models.EditGlossaryRequest{Name: "Shop"})
+	if err != nil {
+		t.Fatalf("EditGlossary() error = %v", err)
+	}
+	if edited.Name != "Shop" || len(edited.Dictionaries) != 2 {
+		t.Errorf("EditGlossary() got=%+v, want name Shop and 2 dictionaries", edited)
+	}
+
+	if err := fake.DeleteAllLangDictionaries(ctx, id, pair); err != nil {
+		t.Fatalf("DeleteAllLangDictionaries() error = %v", err)
+	}
+	if _, err := fake.GetGlossaryEntries(ctx, id, pair); !errors.Is(err, godeeplapi.ErrNotFound) {
+		t.Errorf("GetGlossaryEntries() error = %v, want %v", err, godeeplapi.ErrNotFound)
+	}
+
+	list, err := fake.ListAllGlossaries(ctx)
+	if err != nil || len(list.Glossaries) != 1 {
+		t.Fatalf("ListAllGlossaries() got=%+v, err=%v", list, err)
+	}
+	if err := fake.DeleteGlossary(ctx, id); err != nil {
+		t.Fatalf("DeleteGlossary() error = %v", err)
+	}
+	if _, err := fake.GetGlossaryByID(ctx, id); !errors.Is(err, godeeplapi.ErrNotFound) {
+		t.Errorf("GetGlossaryByID() error = %v, want %v", err, godeeplapi.ErrNotFound)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	fake := godeeplapi.NewFake(nil)
+	fake.ImproveTextFunc = func(ctx context.Context, req models.RephraseRequest) (string, error) {
+		return "Improved", nil
+	}
+	rec := godeeplapi.NewRecorder(fake)
+	ctx := context.Background()
+
+	req := models.TranslationRequest{Text: []string{"Hello"}, TargetLang: "DE"}
+	if _, err := rec.Translate(ctx, req); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if got, _ := rec.ImproveText(ctx, models.RephraseRequest{Text: []string{"hello"}}); got != "Improved" {
+		t.Errorf("ImproveText() got=%q, want %q", got, "Improved")
+	}
+	if err := rec.DeleteGlossary(ctx, "missing"); !errors.Is(err, godeeplapi.ErrNotFound) {
+		t.Errorf("DeleteGlossary() error = %v, want %v", err, godeeplapi.ErrNotFound)
+	}
+
+	calls := rec.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("Calls() got %d calls, want 3", len(calls))
+	}
+	translate := rec.CallsTo("Translate")
+	if len(translate) != 1 {
+		t.Fatalf("CallsTo(Translate) got %d calls, want 1", len(translate))
+	}
+	if got := translate[0].Args[0].(models.TranslationRequest); got.TargetLang != "DE" {
+		t.Errorf("recorded request got=%+v", got)
+	}
+	if got := translate[0].Result.([]string); got[0] != "de:Hello" {
+		t.Errorf("recorded result got=%q", got)
+	}
+	if calls[2].Method != "DeleteGlossary" || calls[2].Err == nil {
+		t.Errorf("recorded call got=%+v, want a failed DeleteGlossary", calls[2])
+	}
+
+	rec.Reset()
+	if len(rec.Calls()) != 0 {
+		t.Errorf("Calls() after Reset got %d calls", len(rec.Calls()))
+	}
+}
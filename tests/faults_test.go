@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/deepltest"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+func faultClient(srv *deepltest.Server, transport *deepltest.FaultTransport, opts ...godeeplapi.ClientOption) *godeeplapi.Client {
+	return srv.Client(append([]godeeplapi.ClientOption{godeeplapi.WithHTTPClient(&http.Client{Transport: transport})}, opts...)...)
+}
+
+func TestFaultTransport_Retry(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+	req := models.TranslationRequest{Text: []string{"Hello"}, TargetLang: "DE"}
+	retry := godeeplapi.WithRetry(3, time.Millisecond)
+
+	tests := []struct {
+		name         string
+		fault        deepltest.TransportFault
+		opts         []godeeplapi.ClientOption
+		wantStatus   int
+		wantInjected int
+	}{
+		{name: "503 retried", fault: deepltest.TransportFault{Path: "/translate", Status: http.StatusServiceUnavailable, Times: 2}, opts: []godeeplapi.ClientOption{retry}, wantInjected: 2},
+		{name: "429 retried", fault: deepltest.TransportFault{Status: http.StatusTooManyRequests, RetryAfter: time.Second, Times: 1}, opts: []godeeplapi.ClientOption{retry}, wantInjected: 1},
+		{name: "timeout retried", fault: deepltest.TransportFault{Err: deepltest.ErrTimeout, Times: 1}, opts: []godeeplapi.ClientOption{retry}, wantInjected: 1},
+		{name: "truncated body retried", fault: deepltest.TransportFault{Truncate: true, TruncateAt: 10, Times: 1}, opts: []godeeplapi.ClientOption{retry}, wantInjected: 1},
+		{name: "456 not retried", fault: deepltest.TransportFault{Status: 456, Times: 2}, opts: []godeeplapi.ClientOption{retry}, wantStatus: 456, wantInjected: 1},
+		{name: "503 without retry", fault: deepltest.TransportFault{Status: http.StatusServiceUnavailable, Times: 2}, wantStatus: http.StatusServiceUnavailable, wantInjected: 1},
+		{name: "retries exhausted", fault: deepltest.TransportFault{Status: http.StatusInternalServerError}, opts: []godeeplapi.ClientOption{retry}, wantStatus: http.StatusInternalServerError, wantInjected: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := deepltest.NewFaultTransport(nil, tt.fault)
+			_, err := faultClient(srv, transport, tt.opts...).Translate(context.Background(), req)
+			if got := apiStatus(err); got != tt.wantStatus {
+				t.Errorf("Translate() error = %v, want status %d", err, tt.wantStatus)
+			}
+			if got := transport.Injected(); got != tt.wantInjected {
+				t.Errorf("Injected() got=%d, want %d", got, tt.wantInjected)
+			}
+		})
+	}
+}
+
+func TestFaultTransport_SlowResponse(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+	transport := deepltest.NewFaultTransport(nil, deepltest.TransportFault{Delay: time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := faultClient(srv, transport).Translate(ctx, models.TranslationRequest{Text: []string{"Hello"}, TargetLang: "DE"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Translate() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestFaultTransport_DocumentPolling(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+	transport := deepltest.NewFaultTransport(nil, deepltest.TransportFault{
+		Method: "POST",
+		Path:   "/document/",
+		Status: http.StatusServiceUnavailable,
+		Times:  1,
+	})
+	fileReq := func() models.FileTranslationRequest {
+		return models.FileTranslationRequest{File: strings.NewReader("Hello"), FileName: "hello.txt", TargetLang: "DE"}
+	}
+
+	_, err := faultClient(srv, transport).TranslateFile(context.Background(), fileReq(), t.TempDir())
+	if apiStatus(err) != http.StatusServiceUnavailable {
+		t.Errorf("TranslateFile() error = %v, want status 503", err)
+	}
+
+	transport = deepltest.NewFaultTransport(nil, deepltest.TransportFault{Method: "POST", Path: "/document/", Status: http.StatusServiceUnavailable, Times: 1})
+	if _, err := faultClient(srv, transport, godeeplapi.WithRetry(2, time.Millisecond)).TranslateFile(context.Background(), fileReq(), t.TempDir()); err != nil {
+		t.Errorf("TranslateFile() with retries error = %v", err)
+	}
+}
+
+func TestFaultTransport_Probability(t *testing.T) {
+	srv := deepltest.NewServer()
+	defer srv.Close()
+	transport := deepltest.NewFaultTransport(nil, deepltest.TransportFault{Status: http.StatusServiceUnavailable, Probability: 0.3})
+	transport.Seed(42)
+	client := faultClient(srv, transport)
+
+	for i := 0; i < 100; i++ {
+		client.GetUsageAndLimits(context.Background())
+	}
+	if got := transport.Injected(); got < 15 || got > 45 {
+		t.Errorf("Injected() got=%d of 100, want about 30", got)
+	}
+}
@@ -2,8 +2,11 @@ package tests
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"github.com/AdolfZahid1/godeeplapi"
 	"github.com/AdolfZahid1/godeeplapi/models"
+	"net/http"
 	"os"
 	"testing"
 )
@@ -54,3 +57,36 @@ func TestClient_ImproveText(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_ImproveTextWireFormat(t *testing.T) {
+	var sent map[string]any
+	client := godeeplapi.NewClient("test-key", false, godeeplapi.WithHTTPClient(&http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			if r.URL.Path != "/v2/write/rephrase" {
+				t.Errorf("path got=%q, want /v2/write/rephrase", r.URL.Path)
+			}
+			json.NewDecoder(r.Body).Decode(&sent)
+			// The response shape documented for POST /v2/write/rephrase
+			return jsonResponse(http.StatusOK, `{"improvements":[{"text":"This is fine.","target_language":"en-GB","detected_source_language":"en"}]}`), nil
+		}),
+	}))
+
+	got, err := client.ImproveText(context.Background(), models.RephraseRequest{Text: []string{"this is fine"}, TargetLanguage: "en-GB", Tone: models.ToneFriendly})
+	if err != nil {
+		t.Fatalf("ImproveText() error = %v", err)
+	}
+	if got != "This is fine." {
+		t.Errorf("ImproveText() got=%q, want %q", got, "This is fine.")
+	}
+	if fmt.Sprint(sent["text"]) != "[this is fine]" || sent["target_lang"] != "en-GB" || sent["tone"] != "friendly" {
+		t.Errorf("request body got=%v", sent)
+	}
+
+	var response models.RephraseResponse
+	if err := json.Unmarshal([]byte(`{"improvements":[{"text":"A","target_language":"de","detected_source_language":"de"}]}`), &response); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(response.Improvements) != 1 || response.Improvements[0].TargetLanguage != "de" || response.Improvements[0].SourceLanguage != "de" {
+		t.Errorf("RephraseResponse got=%+v", response)
+	}
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AdolfZahid1/godeeplapi/formats/jsonlocale"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+const jsonSource = `{
+  "title": "Welcome, {{name}}!",
+  "count": 3,
+  "enabled": true,
+  "nav": {
+    "home": "Home",
+    "items": [
+      "One & two",
+      "<b>"
+    ]
+  },
+  "apple_one": "{{count}} apple",
+  "apple_other": "{{count}} apples",
+  "empty": null
+}
+`
+
+func TestJSONLocale_ParseWriteRoundTrip(t *testing.T) {
+	v, err := jsonlocale.Parse(strings.NewReader(jsonSource))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := v.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got := buf.String(); got != jsonSource {
+		t.Errorf("round trip mismatch:\n%s\nwant:\n%s", got, jsonSource)
+	}
+}
+
+func TestJSONLocale_Translate(t *testing.T) {
+	source, err := jsonlocale.Parse(strings.NewReader(jsonSource))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	existing, _ := jsonlocale.Parse(strings.NewReader(`{"title": "Willkommen, {{name}}!", "nav": {"home": "Start"}}`))
+	previous, _ := jsonlocale.Parse(strings.NewReader(`{"title": "Welcome, {{name}}!", "nav": {"home": "Homepage"}}`))
+
+	stub := &stubTranslator{}
+	got, err := jsonlocale.Translate(context.Background(), stub, source,
+		models.TranslationRequest{TargetLang: models.TargetLanguage.Russian},
+		jsonlocale.WithExisting(existing), jsonlocale.WithPreviousSource(previous))
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		path []string
+		want string
+	}{
+		{name: "unchanged key reused", path: []string{"title"}, want: "Willkommen, {{name}}!"},
+		{name: "changed key translated", path: []string{"nav", "home"}, want: "ru:Home"},
+		{name: "array item escaped and restored", path: []string{"nav", "items", "0"}, want: "ru:One & two"},
+		{name: "plural one", path: []string{"apple_one"}, want: "ru:{{count}} apple"},
+		{name: "plural few from other", path: []string{"apple_few"}, want: "ru:{{count}} apples"},
+		{name: "plural many from other", path: []string{"apple_many"}, want: "ru:{{count}} apples"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := got.Lookup(tt.path...)
+			if v == nil {
+				t.Fatalf("missing key %v", tt.path)
+			}
+			if v.Str != tt.want {
+				t.Errorf("got=%q, want=%q", v.Str, tt.want)
+			}
+		})
+	}
+
+	if v := got.Lookup("count"); v == nil || string(v.RawJSON) != "3" {
+		t.Errorf("non-string value was not preserved")
+	}
+	if len(stub.requests) != 1 || stub.requests[0].TagHandling != models.TagXML {
+		t.Fatalf("expected one xml request, got %+v", stub.requests)
+	}
+	for _, text := range stub.requests[0].Text {
+		if strings.Contains(text, "{{") {
+			t.Errorf("placeholder was sent unprotected: %q", text)
+		}
+	}
+}
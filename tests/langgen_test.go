@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/AdolfZahid1/godeeplapi/internal/langgen"
+)
+
+func TestLanggen_GeneratedFileIsCurrent(t *testing.T) {
+	snapshot, err := os.ReadFile("../models/languages.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := langgen.Generate(snapshot, "languages.json")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	got, err := os.ReadFile("../models/languages.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("models/languages.go is out of date, run go generate ./models")
+	}
+}
+
+func TestLanggen_FieldName(t *testing.T) {
+	tests := []struct {
+		lang langgen.Language
+		want string
+	}{
+		{lang: langgen.Language{Language: "NB", Name: "Norwegian Bokmål"}, want: "Norwegian"},
+		{lang: langgen.Language{Language: "EN-GB", Name: "English (British)"}, want: "EnglishGB"},
+		{lang: langgen.Language{Language: "pt-BR", Name: "Portuguese (Brazilian)"}, want: "PortugueseBR"},
+		{lang: langgen.Language{Language: "ZH", Name: "Chinese (simplified)"}, want: "ChineseSimpl"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang.Language, func(t *testing.T) {
+			if got := langgen.FieldName(tt.lang); got != tt.want {
+				t.Errorf("FieldName() got=%q, want=%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLanggen_Generate(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot string
+		want     []string
+		wantErr  string
+	}{
+		{
+			name: "tables",
+			snapshot: `{"source":[{"language":"DE","name":"German"}],
+				"target":[{"language":"DE","name":"German","supports_formality":true},{"language":"EN-GB","name":"English (British)"}],
+				"write":[{"language":"de","name":"German"}]}`,
+			want: []string{
+				"// Code generated by genlanguages from snap.json. DO NOT EDIT.",
+				"EnglishGB: \"EN-GB\",",
+				"var formalityLanguages = map[Language]bool{\n\t\"DE\": true,\n}",
+				"var targetVariants = map[Language]bool{\n\t\"EN-GB\": true,\n}",
+				"var ImproveTextLanguages = ImproveTextLanguage{\n\tGerman: \"de\",\n}",
+			},
+		},
+		{
+			name:     "duplicate field",
+			snapshot: `{"source":[{"language":"DE","name":"German"},{"language":"DE-X","name":"German (other)"}],"target":[{"language":"DE","name":"German"}]}`,
+			wantErr:  "both map to field German",
+		},
+		{
+			name:     "empty",
+			snapshot: `{}`,
+			wantErr:  "no source or target languages",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := langgen.Generate([]byte(tt.snapshot), "snap.json")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Generate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(string(got), w) {
+					t.Errorf("output does not contain %q:\n%s", w, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+// languagesAPI fakes the languages and glossary language pairs endpoints and
+// counts the calls per endpoint and type.
+type languagesAPI struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (a *languagesAPI) client(opts ...godeeplapi.ClientOption) *godeeplapi.Client {
+	a.calls = make(map[string]int)
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		a.mu.Lock()
+		a.calls[r.URL.Path+"?"+r.URL.RawQuery]++
+		a.mu.Unlock()
+
+		switch {
+		case r.URL.Path == "/v2/glossary-language-pairs":
+			return jsonResponse(http.StatusOK, `{"supported_languages":[{"source_lang":"en","target_lang":"de"},{"source_lang":"de","target_lang":"en"}]}`), nil
+		case r.URL.Path == "/v2/languages" && r.URL.Query().Get("type") == "target":
+			return jsonResponse(http.StatusOK, `[{"language":"DE","name":"German","supports_formality":true},{"language":"EN-GB","name":"English (British)"},{"language":"EN-US","name":"English (American)"}]`), nil
+		case r.URL.Path == "/v2/languages" && r.URL.Query().Get("type") == "source":
+			return jsonResponse(http.StatusOK, `[{"language":"DE","name":"German"},{"language":"EN","name":"English"}]`), nil
+		case r.URL.Path == "/v2/translate":
+			return jsonResponse(http.StatusOK, `{"translations":[{"detected_source_language":"EN","text":"Hallo"}]}`), nil
+		}
+		return jsonResponse(http.StatusNotFound, `{"message":"not found"}`), nil
+	})
+	opts = append([]godeeplapi.ClientOption{godeeplapi.WithHTTPClient(&http.Client{Transport: transport})}, opts...)
+	return godeeplapi.NewClient("test-key", false, opts...)
+}
+
+func TestLanguageRegistry(t *testing.T) {
+	ctx := context.Background()
+	api := &languagesAPI{}
+	registry := godeeplapi.NewLanguageRegistry(api.client(), time.Hour)
+
+	tests := []struct {
+		name  string
+		check func() (bool, error)
+		want  bool
+	}{
+		{name: "target variant", check: func() (bool, error) { return registry.IsValidTarget(ctx, "en-gb") }, want: true},
+		{name: "bare target uses default variant", check: func() (bool, error) { return registry.IsValidTarget(ctx, "EN") }, want: true},
+		{name: "unknown target", check: func() (bool, error) { return registry.IsValidTarget(ctx, "FR") }, want: false},
+		{name: "source from variant", check: func() (bool, error) { return registry.IsValidSource(ctx, "EN-US") }, want: true},
+		{name: "formality", check: func() (bool, error) { return registry.SupportsFormality(ctx, "DE") }, want: true},
+		{name: "no formality", check: func() (bool, error) { return registry.SupportsFormality(ctx, "EN-US") }, want: false},
+		{name: "glossary pair", check: func() (bool, error) { return registry.SupportsGlossaryPair(ctx, "EN", "de") }, want: true},
+		{name: "unsupported glossary pair", check: func() (bool, error) { return registry.SupportsGlossaryPair(ctx, "DE", "FR") }, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.check()
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got=%v, want=%v", got, tt.want)
+			}
+		})
+	}
+
+	targets, err := registry.TargetLanguages(ctx)
+	if err != nil || len(targets) != 3 || targets[0].Language != "DE" {
+		t.Errorf("TargetLanguages() = %v, %v", targets, err)
+	}
+	for _, path := range []string{"/v2/languages?type=source", "/v2/languages?type=target", "/v2/glossary-language-pairs?"} {
+		if got := api.calls[path]; got != 1 {
+			t.Errorf("calls to %s: got=%d, want=1", path, got)
+		}
+	}
+
+	if err := registry.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if got := api.calls["/v2/languages?type=target"]; got != 2 {
+		t.Errorf("calls after Refresh: got=%d, want=2", got)
+	}
+}
+
+func TestLanguageRegistry_TTL(t *testing.T) {
+	ctx := context.Background()
+	api := &languagesAPI{}
+	registry := godeeplapi.NewLanguageRegistry(api.client(), time.Millisecond)
+
+	if _, err := registry.IsValidTarget(ctx, "DE"); err != nil {
+		t.Fatalf("IsValidTarget() error = %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, err := registry.IsValidTarget(ctx, "DE"); err != nil {
+		t.Fatalf("IsValidTarget() error = %v", err)
+	}
+	if got := api.calls["/v2/languages?type=target"]; got != 2 {
+		t.Errorf("calls after expiry: got=%d, want=2", got)
+	}
+}
+
+func TestClient_TranslateValidatesLanguagesWithRegistry(t *testing.T) {
+	api := &languagesAPI{}
+	client := api.client(godeeplapi.WithLanguageRegistry(time.Hour))
+
+	tests := []struct {
+		name       string
+		req        models.TranslationRequest
+		wantFields []string
+	}{
+		{
+			name: "supported",
+			req:  models.TranslationRequest{Text: []string{"Hello"}, SourceLang: "EN", TargetLang: "DE", Formality: models.FormalityMore},
+		},
+		{
+			name:       "unsupported source and formality",
+			req:        models.TranslationRequest{Text: []string{"Hello"}, TargetLang: "EN-GB", SourceLang: "FR", Formality: models.FormalityLess},
+			wantFields: []string{"SourceLang", "Formality"},
+		},
+		{
+			name:       "unknown target",
+			req:        models.TranslationRequest{Text: []string{"Hello"}, TargetLang: "XX"},
+			wantFields: []string{"TargetLang"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Translate(context.Background(), tt.req)
+			checkValidation(t, err, tt.wantFields)
+		})
+	}
+	if got := api.calls["/v2/languages?type=target"]; got != 1 {
+		t.Errorf("language list fetched %d times, want 1", got)
+	}
+	if client.Languages() == nil {
+		t.Error("Languages() = nil")
+	}
+}
+
+func TestClient_CreateGlossaryChecksPairWithRegistry(t *testing.T) {
+	api := &languagesAPI{}
+	client := api.client(godeeplapi.WithLanguageRegistry(time.Hour))
+
+	_, err := client.CreateGlossary(context.Background(), models.CreateGlossaryRequest{
+		Name:         "Terms",
+		Dictionaries: []models.Dictionary{{SourceLanguage: "de", TargetLanguage: "fr", Entries: "Hallo\tBonjour"}},
+	})
+	var verr *models.ValidationError
+	if !errors.As(err, &verr) || !verr.Has("Dictionaries[0].TargetLanguage") {
+		t.Errorf("CreateGlossary() error = %v", err)
+	}
+}
+
+func TestLanguageRegistry_SharesFetches(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	release := make(chan struct{})
+	client := godeeplapi.NewClient("test-key", false, godeeplapi.WithHTTPClient(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		<-release
+		if r.URL.Path == "/v2/glossary-language-pairs" {
+			return jsonResponse(http.StatusOK, `{"supported_languages":[]}`), nil
+		}
+		return jsonResponse(http.StatusOK, `[{"language":"DE","name":"German"}]`), nil
+	})}))
+	registry := godeeplapi.NewLanguageRegistry(client, time.Hour)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ok, err := registry.IsValidTarget(context.Background(), "DE"); err != nil || !ok {
+				errs <- fmt.Errorf("IsValidTarget() = %v, %v", ok, err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if calls != 3 {
+		t.Errorf("requests: got=%d, want=3", calls)
+	}
+}
+
+func TestLanguageRegistry_RemembersFailures(t *testing.T) {
+	calls := 0
+	client := godeeplapi.NewClient("test-key", false, godeeplapi.WithHTTPClient(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		return jsonResponse(http.StatusServiceUnavailable, `{"message":"Service unavailable"}`), nil
+	})}))
+	registry := godeeplapi.NewLanguageRegistry(client, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		if _, err := registry.IsValidTarget(context.Background(), "DE"); apiStatus(err) != http.StatusServiceUnavailable {
+			t.Fatalf("IsValidTarget() error = %v, want status 503", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("requests after failures: got=%d, want=1", calls)
+	}
+}
@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/models"
+	"golang.org/x/text/language"
+)
+
+func TestParseLanguage(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    models.Language
+		wantErr bool
+	}{
+		{in: "de", want: "DE"},
+		{in: "de-AT", want: "DE"},
+		{in: "en-gb", want: "EN-GB"},
+		{in: "en_US", want: "EN-US"},
+		{in: "pt-BR", want: "PT-BR"},
+		{in: "es-419", want: "ES-419"},
+		{in: "zh", want: "ZH"},
+		{in: "zh-Hant-TW", want: "ZH-HANT"},
+		{in: "zh-CN", want: "ZH-HANS"},
+		{in: "no", want: "NB"},
+		{in: "not a tag!", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := models.ParseLanguage(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLanguage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLanguage() got=%q, want=%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLanguage_Codes(t *testing.T) {
+	tests := []struct {
+		lang       models.Language
+		wantSource models.Language
+		wantTarget models.Language
+	}{
+		{lang: "EN", wantSource: "EN", wantTarget: "EN-US"},
+		{lang: "en-gb", wantSource: "EN", wantTarget: "EN-GB"},
+		{lang: models.TargetLanguage.PortugueseBR, wantSource: "PT", wantTarget: "PT-BR"},
+		{lang: models.SourceLanguage.Portuguese, wantSource: "PT", wantTarget: "PT-PT"},
+		{lang: models.TargetLanguage.ChineseHans, wantSource: "ZH", wantTarget: "ZH-HANS"},
+		{lang: "de", wantSource: "DE", wantTarget: "DE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang.String(), func(t *testing.T) {
+			if got := tt.lang.SourceCode(); got != tt.wantSource {
+				t.Errorf("SourceCode() got=%q, want=%q", got, tt.wantSource)
+			}
+			if got := tt.lang.TargetCode(); got != tt.wantTarget {
+				t.Errorf("TargetCode() got=%q, want=%q", got, tt.wantTarget)
+			}
+		})
+	}
+
+	lang, err := models.LanguageFromTag(language.BrazilianPortuguese)
+	if err != nil || lang != models.TargetLanguage.PortugueseBR {
+		t.Errorf("LanguageFromTag() got=%q, %v", lang, err)
+	}
+	if got := models.TargetLanguage.EnglishGB.Tag(); got != language.BritishEnglish {
+		t.Errorf("Tag() got=%v, want=%v", got, language.BritishEnglish)
+	}
+}
+
+func TestLanguage_Locale(t *testing.T) {
+	tests := []struct {
+		lang models.Language
+		sep  string
+		want string
+	}{
+		{lang: "DE", sep: "-", want: "de"},
+		{lang: "PT-BR", sep: "-", want: "pt-BR"},
+		{lang: "pt-br", sep: "_", want: "pt_BR"},
+		{lang: "ZH-HANT", sep: "-", want: "zh-Hant"},
+		{lang: "ZH-HANS", sep: "_", want: "zh_Hans"},
+		{lang: "ES-419", sep: "-", want: "es-419"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang.String()+tt.sep, func(t *testing.T) {
+			if got := tt.lang.Locale(tt.sep); got != tt.want {
+				t.Errorf("Locale(%q) got=%q, want=%q", tt.sep, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_TranslateNormalizesLanguages(t *testing.T) {
+	var sent models.TranslationRequest
+	client := godeeplapi.NewClient("test-key", false,
+		godeeplapi.WithHTTPClient(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(body, &sent); err != nil {
+				t.Fatalf("invalid request body: %v", err)
+			}
+			return jsonResponse(http.StatusOK, `{"translations":[{"detected_source_language":"DE","text":"Hello"}]}`), nil
+		})}))
+
+	_, err := client.Translate(context.Background(), models.TranslationRequest{
+		Text:       []string{"Hallo"},
+		SourceLang: "de-DE",
+		TargetLang: "en",
+	})
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if sent.SourceLang != "DE" || sent.TargetLang != "EN-US" {
+		t.Errorf("sent languages: source=%q, target=%q", sent.SourceLang, sent.TargetLang)
+	}
+}
@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AdolfZahid1/godeeplapi/formats/po"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+const poSource = `# Translation template.
+msgid ""
+msgstr ""
+"Project-Id-Version: demo\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+# Shown on the main menu
+#. TRANSLATORS: keep it short
+#: main.c:10
+msgctxt "menu"
+msgid "Open"
+msgstr ""
+
+#: main.c:20
+#, c-format
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+
+#, fuzzy
+#| msgid "Old"
+msgid "Close"
+msgstr "Schliessen"
+
+msgid "Done"
+msgstr "Fertig"
+
+msgid ""
+"Multi\n"
+"line"
+msgstr ""
+
+#~ msgid "Gone"
+#~ msgstr "Weg"
+`
+
+func TestPO_ParseWriteRoundTrip(t *testing.T) {
+	f, err := po.Parse(strings.NewReader(poSource))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := len(f.Entries); got != 7 {
+		t.Fatalf("entries: got=%d, want=7", got)
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got := buf.String(); got != poSource {
+		t.Errorf("round trip mismatch:\n%s\nwant:\n%s", got, poSource)
+	}
+}
+
+func TestPO_Translate(t *testing.T) {
+	f, err := po.Parse(strings.NewReader(poSource))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	stub := &stubTranslator{}
+	n, err := po.Translate(context.Background(), stub, f, models.TranslationRequest{TargetLang: models.TargetLanguage.German})
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if n != 4 {
+		t.Errorf("translated entries: got=%d, want=4", n)
+	}
+
+	tests := []struct {
+		name  string
+		entry int
+		want  []string
+	}{
+		{name: "context entry", entry: 1, want: []string{"de:Open"}},
+		{name: "plural entry", entry: 2, want: []string{"de:%d file", "de:%d files"}},
+		{name: "fuzzy entry", entry: 3, want: []string{"de:Close"}},
+		{name: "translated entry", entry: 4, want: []string{"Fertig"}},
+		{name: "obsolete entry", entry: 6, want: []string{"Weg"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := f.Entries[tt.entry].Msgstr
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("msgstr: got=%q, want=%q", got, tt.want)
+			}
+		})
+	}
+
+	if f.Entries[3].IsFuzzy() {
+		t.Errorf("fuzzy flag should be cleared after translation")
+	}
+	if got := f.HeaderField("Language"); got != "de" {
+		t.Errorf("Language header: got=%q, want=%q", got, "de")
+	}
+
+	var contextSent bool
+	for _, r := range stub.requests {
+		if r.Context == "menu\nTRANSLATORS: keep it short\nShown on the main menu" {
+			contextSent = true
+		}
+	}
+	if !contextSent {
+		t.Errorf("msgctxt and comments were not sent as Context")
+	}
+}
+
+func TestPO_TranslateFailureKeepsFile(t *testing.T) {
+	f, err := po.Parse(strings.NewReader(poSource))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	// The first context group succeeds and the second one fails.
+	failing := &failingTranslator{n: 1}
+	if _, err := po.Translate(context.Background(), failing, f, models.TranslationRequest{TargetLang: models.TargetLanguage.German}); err == nil {
+		t.Fatalf("Translate() error = nil, want an error")
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got := buf.String(); got != poSource {
+		t.Errorf("file changed by a failed translation:\n%s\nwant:\n%s", got, poSource)
+	}
+}
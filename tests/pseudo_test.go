@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/formats/jsonlocale"
+	"github.com/AdolfZahid1/godeeplapi/models"
+	"github.com/AdolfZahid1/godeeplapi/pseudo"
+)
+
+var _ godeeplapi.Translator = (*pseudo.Translator)(nil)
+
+func TestPseudo_Pseudolocalize(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []pseudo.Option
+		in   string
+		want string
+	}{
+		{name: "default", in: "Save changes", want: "[Šàvé çĥàñĝéš~~~~]"},
+		{name: "empty", in: "", want: ""},
+		{name: "tags and entities", in: `<a href="/x">Home</a> &amp; more`, want: `[<a href="/x">Ĥömé</a> &amp; möŕé~~~]`},
+		{name: "placeholders", in: "Hi {{name}}, %1$s has {count} items", want: "[Ĥî {{name}}, %1$s ĥàš {count} îţémš~~~~~]"},
+		{name: "no accents", opts: []pseudo.Option{pseudo.WithoutAccents(), pseudo.WithExpansion(0.5), pseudo.WithMarkers("⟦", "⟧")}, in: "Hello", want: "⟦Hello~~~⟧"},
+		{name: "no expansion", opts: []pseudo.Option{pseudo.WithExpansion(0)}, in: "Hello", want: "[Ĥéļļö]"},
+		{name: "nothing protected", opts: []pseudo.Option{pseudo.WithProtected(nil), pseudo.WithExpansion(0)}, in: "Hi {name}", want: "[Ĥî {ñàmé}]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pseudo.New(tt.opts...).Pseudolocalize(tt.in); got != tt.want {
+				t.Errorf("Pseudolocalize() got=%q, want=%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_OfflinePseudoTranslator(t *testing.T) {
+	client := godeeplapi.NewClient("", false, godeeplapi.WithOfflineTranslator(pseudo.New()))
+
+	source, err := jsonlocale.Parse(strings.NewReader(`{"greeting": "Welcome, {{name}}!", "files": "Open {{count}} files"}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	got, err := jsonlocale.Translate(context.Background(), client, source,
+		models.TranslationRequest{TargetLang: models.TargetLanguage.German})
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "greeting", want: "[Ŵéļçömé, {{name}}!~~~]"},
+		{key: "files", want: "[Öpéñ {{count}} fîļéš~~~~]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			v := got.Get(tt.key)
+			if v == nil || v.Str != tt.want {
+				t.Errorf("%s got=%+v, want=%q", tt.key, v, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package tests
+
+import (
+	"context"
+	"strings"
+
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+// stubTranslator "translates" by prefixing each text with the target language
+// and records the requests it received.
+type stubTranslator struct {
+	requests []models.TranslationRequest
+}
+
+func (s *stubTranslator) Translate(_ context.Context, request models.TranslationRequest) ([]string, error) {
+	s.requests = append(s.requests, request)
+	out := make([]string, len(request.Text))
+	for i, text := range request.Text {
+		out[i] = strings.ToLower(request.TargetLang.String()) + ":" + text
+	}
+	return out, nil
+}
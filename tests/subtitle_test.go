@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AdolfZahid1/godeeplapi/formats/subtitle"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+const srtSource = `1
+00:00:01,000 --> 00:00:03,500
+<i>Welcome</i> to the
+training video.
+
+2
+00:00:04,000 --> 00:00:06,000
+- Are you ready?
+- Yes.
+
+`
+
+const vttSource = `WEBVTT - Training
+
+NOTE speaker notes
+
+intro
+00:01.000 --> 00:03.500 line:0
+<v Trainer>Hello & welcome</v>
+
+`
+
+func TestSubtitle_ParseWriteRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+		format subtitle.Format
+	}{
+		{name: "SRT", source: srtSource, want: srtSource, format: subtitle.SRT},
+		{name: "WebVTT", source: vttSource, want: strings.Replace(vttSource, "00:01.000 --> 00:03.500", "00:00:01.000 --> 00:00:03.500", 1), format: subtitle.WebVTT},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := subtitle.Parse(strings.NewReader(tt.source))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if f.Format != tt.format {
+				t.Errorf("Format: got=%v, want=%v", f.Format, tt.format)
+			}
+			var buf bytes.Buffer
+			if err := f.Write(&buf); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("round trip mismatch:\n%s\nwant:\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSubtitle_Translate(t *testing.T) {
+	f, err := subtitle.Parse(strings.NewReader(srtSource))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	stub := &stubTranslator{}
+	got, err := subtitle.Translate(context.Background(), stub, f,
+		models.TranslationRequest{TargetLang: models.TargetLanguage.German},
+		subtitle.WithBatchSize(1), subtitle.WithMaxLineLength(20))
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+
+	if lines := got.Cues[0].Lines; strings.Join(lines, "|") != "de:<i>Welcome</i> to the|training video." {
+		t.Errorf("re-wrapped cue: got=%q", lines)
+	}
+	if lines := got.Cues[1].Lines; strings.Join(lines, "|") != "de:- Are you ready?|de:- Yes." {
+		t.Errorf("dialogue cue: got=%q", lines)
+	}
+	if got.Cues[1].Start != f.Cues[1].Start || got.Cues[1].ID != "2" {
+		t.Errorf("timing or identifier changed")
+	}
+	if f.Cues[0].Lines[0] != "<i>Welcome</i> to the" {
+		t.Errorf("source file was modified")
+	}
+
+	if len(stub.requests) != 2 {
+		t.Fatalf("requests: got=%d, want=2", len(stub.requests))
+	}
+	if ctx := stub.requests[0].Context; ctx != "- Are you ready? - Yes." {
+		t.Errorf("Context of first batch: got=%q", ctx)
+	}
+	if text := stub.requests[0].Text[0]; strings.Contains(text, "<i>") {
+		t.Errorf("styling tag sent unprotected: %q", text)
+	}
+}
+
+func TestSubtitle_TranslateDialogueBatches(t *testing.T) {
+	var src strings.Builder
+	for i := 1; i <= 20; i++ {
+		fmt.Fprintf(&src, "%d\n00:00:%02d,000 --> 00:00:%02d,500\n- One?\n- Two.\n- Three!\n\n", i, i, i)
+	}
+	f, err := subtitle.Parse(strings.NewReader(src.String()))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	stub := &stubTranslator{}
+	got, err := subtitle.Translate(context.Background(), stub, f,
+		models.TranslationRequest{TargetLang: models.TargetLanguage.German})
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+
+	if len(stub.requests) != 2 {
+		t.Fatalf("requests: got=%d, want=2", len(stub.requests))
+	}
+	for i, r := range stub.requests {
+		if len(r.Text) > 50 {
+			t.Errorf("request %d: got %d texts, want at most 50", i, len(r.Text))
+		}
+		if r.Context != stub.requests[0].Context {
+			t.Errorf("request %d: Context=%q, want the batch context %q", i, r.Context, stub.requests[0].Context)
+		}
+	}
+	if lines := got.Cues[19].Lines; strings.Join(lines, "|") != "de:- One?|de:- Two.|de:- Three!" {
+		t.Errorf("last cue: got=%q", lines)
+	}
+}
@@ -0,0 +1,304 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/models"
+	"github.com/AdolfZahid1/godeeplapi/tm"
+)
+
+// roundTripFunc lets tests answer HTTP requests without a network.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+const tmxSource = `<?xml version="1.0" encoding="UTF-8"?>
+<tmx version="1.4">
+  <header creationtool="test" creationtoolversion="1" segtype="sentence" o-tmf="test" adminlang="en" srclang="en" datatype="plaintext"/>
+  <body>
+    <tu>
+      <tuv xml:lang="en"><seg>Save  <bpt i="1">&lt;b&gt;</bpt>changes<ept i="1">&lt;/b&gt;</ept></seg></tuv>
+      <tuv xml:lang="de"><seg>Änderungen speichern</seg></tuv>
+      <tuv xml:lang="fr"><seg>Enregistrer les modifications</seg></tuv>
+    </tu>
+  </body>
+</tmx>
+`
+
+func TestTM_ImportExportTMX(t *testing.T) {
+	m := tm.New()
+	n, err := m.ImportTMX(strings.NewReader(tmxSource))
+	if err != nil {
+		t.Fatalf("ImportTMX() error = %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("imported: got=%d, want=2", n)
+	}
+
+	tests := []struct {
+		name       string
+		sourceLang string
+		targetLang string
+		text       string
+		want       string
+		wantOK     bool
+	}{
+		{name: "exact with normalized whitespace", sourceLang: "EN", targetLang: "DE", text: " Save changes ", want: "Änderungen speichern", wantOK: true},
+		{name: "any source language", sourceLang: "", targetLang: "FR", text: "Save changes", want: "Enregistrer les modifications", wantOK: true},
+		{name: "source variant ignored", sourceLang: "EN-US", targetLang: "DE", text: "Save changes", want: "Änderungen speichern", wantOK: true},
+		{name: "miss", sourceLang: "EN", targetLang: "ES", text: "Save changes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := m.Lookup(tt.sourceLang, tt.targetLang, tt.text)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("Lookup() got=(%q, %v), want=(%q, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := m.ExportTMX(&buf); err != nil {
+		t.Fatalf("ExportTMX() error = %v", err)
+	}
+	reimported := tm.New()
+	if _, err := reimported.ImportTMX(&buf); err != nil {
+		t.Fatalf("re-import error = %v", err)
+	}
+	if reimported.Len() != m.Len() {
+		t.Errorf("re-imported entries: got=%d, want=%d", reimported.Len(), m.Len())
+	}
+}
+
+func TestTM_FileBacked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.jsonl")
+
+	m, err := tm.Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if err := m.Add(tm.Entry{SourceLang: "EN", TargetLang: "DE", Source: "Hello", Target: "Hallo"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reopened, err := tm.Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer reopened.Close()
+	if got, ok := reopened.Lookup("EN", "DE", "Hello"); !ok || got != "Hallo" {
+		t.Errorf("Lookup() after reopen got=(%q, %v)", got, ok)
+	}
+}
+
+func TestTM_GetAnySourceLanguage(t *testing.T) {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		entries []tm.Entry
+		want    string
+	}{
+		{
+			name: "newest wins",
+			entries: []tm.Entry{
+				{SourceLang: "EN", TargetLang: "DE", Source: "Hotel", Target: "Hotel (en)", CreatedAt: day.Add(time.Hour)},
+				{SourceLang: "FR", TargetLang: "DE", Source: "Hotel", Target: "Hotel (fr)", CreatedAt: day},
+			},
+			want: "Hotel (en)",
+		},
+		{
+			name: "ties by source language",
+			entries: []tm.Entry{
+				{SourceLang: "NL", TargetLang: "DE", Source: "Hotel", Target: "Hotel (nl)", CreatedAt: day},
+				{SourceLang: "ES", TargetLang: "DE", Source: "Hotel", Target: "Hotel (es)", CreatedAt: day},
+			},
+			want: "Hotel (es)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tm.New()
+			for _, e := range tt.entries {
+				if err := m.Add(e); err != nil {
+					t.Fatalf("Add() error = %v", err)
+				}
+			}
+			// Map iteration order is random, so repeat the lookup.
+			for i := 0; i < 20; i++ {
+				if got, ok := m.Lookup("", "DE", " Hotel "); !ok || got != tt.want {
+					t.Fatalf("Lookup() got=(%q, %v), want %q", got, ok, tt.want)
+				}
+			}
+			if _, ok := m.Lookup("", "FR", "Hotel"); ok {
+				t.Errorf("Lookup() of another target language found an entry")
+			}
+		})
+	}
+}
+
+func TestClient_TranslateWithMemory(t *testing.T) {
+	m := tm.New()
+	m.Add(tm.Entry{SourceLang: "EN", TargetLang: "DE", Source: "Hello", Target: "Hallo (approved)"})
+
+	var sent []string
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		var req models.TranslationRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		sent = append(sent, req.Text...)
+		return jsonResponse(http.StatusOK, `{"translations":[{"detected_source_language":"EN","text":"Welt"}]}`), nil
+	})
+
+	client := godeeplapi.NewClient("test-key", false,
+		godeeplapi.WithHTTPClient(&http.Client{Transport: transport}),
+		godeeplapi.WithTranslationMemory(m))
+
+	got, err := client.TranslateDetailed(context.Background(), models.TranslationRequest{
+		Text:       []string{"Hello", "World"},
+		SourceLang: models.SourceLanguage.English,
+		TargetLang: models.TargetLanguage.German,
+	})
+	if err != nil {
+		t.Fatalf("TranslateDetailed() error = %v", err)
+	}
+
+	if len(sent) != 1 || sent[0] != "World" {
+		t.Errorf("only the miss should be sent, got %v", sent)
+	}
+	if !got[0].FromMemory || got[0].Text != "Hallo (approved)" {
+		t.Errorf("memory hit: got=%+v", got[0])
+	}
+	if got[1].FromMemory || got[1].Text != "Welt" {
+		t.Errorf("API result: got=%+v", got[1])
+	}
+
+	sent = nil
+	if _, err := client.Translate(context.Background(), models.TranslationRequest{
+		Text:       []string{"Hello"},
+		SourceLang: models.SourceLanguage.English,
+		TargetLang: models.TargetLanguage.German,
+	}); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if len(sent) != 0 {
+		t.Errorf("no request expected when every text is a hit, sent %v", sent)
+	}
+}
+
+var _ godeeplapi.FuzzyTranslationMemory = (*tm.Memory)(nil)
+
+func TestTM_Search(t *testing.T) {
+	m := tm.New()
+	m.Add(tm.Entry{SourceLang: "EN", TargetLang: "DE", Source: "Delete 3 files?", Target: "3 Dateien löschen?"})
+	m.Add(tm.Entry{SourceLang: "EN", TargetLang: "DE", Source: "Delete the file.", Target: "Datei löschen."})
+	m.Add(tm.Entry{SourceLang: "EN", TargetLang: "DE", Source: "Open settings", Target: "Einstellungen öffnen"})
+
+	tests := []struct {
+		name      string
+		text      string
+		threshold float64
+		wantFirst string
+		wantCount int
+		minScore  float64
+	}{
+		{name: "different number", text: "Delete 12 files?", threshold: 0.9, wantFirst: "Delete 3 files?", wantCount: 1, minScore: 0.98},
+		{name: "punctuation and whitespace", text: "Delete  the file!", threshold: 0.9, wantFirst: "Delete the file.", wantCount: 1, minScore: 0.9},
+		{name: "below threshold", text: "Close window", threshold: 0.8, wantCount: 0},
+		{name: "low threshold returns several", text: "Delete files", threshold: 0.5, wantFirst: "Delete 3 files?", wantCount: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := m.Search("EN", "DE", tt.text, tt.threshold, 0)
+			if len(got) != tt.wantCount {
+				t.Fatalf("matches: got=%+v, want %d", got, tt.wantCount)
+			}
+			if tt.wantCount == 0 {
+				return
+			}
+			if got[0].Source != tt.wantFirst {
+				t.Errorf("best match: got=%q, want=%q", got[0].Source, tt.wantFirst)
+			}
+			if got[0].Score < tt.minScore || got[0].Score >= 1 {
+				t.Errorf("score: got=%v, want in [%v, 1)", got[0].Score, tt.minScore)
+			}
+		})
+	}
+}
+
+func TestClient_TranslateWithFuzzyMemory(t *testing.T) {
+	m := tm.New()
+	m.Add(tm.Entry{SourceLang: "EN", TargetLang: "DE", Source: "Delete 3 files?", Target: "3 Dateien löschen?"})
+
+	calls := 0
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		return jsonResponse(http.StatusOK, `{"translations":[{"detected_source_language":"EN","text":"4 Dateien löschen?"}]}`), nil
+	})
+	request := models.TranslationRequest{
+		Text:       []string{"Delete 4 files?"},
+		SourceLang: models.SourceLanguage.English,
+		TargetLang: models.TargetLanguage.German,
+	}
+
+	tests := []struct {
+		name       string
+		text       string
+		opts       godeeplapi.FuzzyOptions
+		wantCalls  int
+		wantText   string
+		fromMemory bool
+	}{
+		{name: "alongside", opts: godeeplapi.FuzzyOptions{Threshold: 0.9}, wantCalls: 1, wantText: "4 Dateien löschen?"},
+		{name: "instead with other numbers", opts: godeeplapi.FuzzyOptions{Threshold: 0.9, UseBestMatch: true}, wantCalls: 1, wantText: "4 Dateien löschen?"},
+		{name: "instead", text: "Delete 3 files!", opts: godeeplapi.FuzzyOptions{Threshold: 0.9, UseBestMatch: true}, wantCalls: 0, wantText: "3 Dateien löschen?", fromMemory: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			client := godeeplapi.NewClient("test-key", false,
+				godeeplapi.WithHTTPClient(&http.Client{Transport: transport}),
+				godeeplapi.WithTranslationMemory(m),
+				godeeplapi.WithFuzzyMatching(tt.opts))
+
+			req := request
+			if tt.text != "" {
+				req.Text = []string{tt.text}
+			}
+			got, err := client.TranslateDetailed(context.Background(), req)
+			if err != nil {
+				t.Fatalf("TranslateDetailed() error = %v", err)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("API calls: got=%d, want=%d", calls, tt.wantCalls)
+			}
+			if got[0].Text != tt.wantText || got[0].FromMemory != tt.fromMemory {
+				t.Errorf("result: got=%+v", got[0])
+			}
+			if len(got[0].MemoryMatches) != 1 || got[0].MemoryMatches[0].Score >= 1 {
+				t.Errorf("matches: got=%+v", got[0].MemoryMatches)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/AdolfZahid1/godeeplapi"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+func TestTranslationRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        models.TranslationRequest
+		wantFields []string
+	}{
+		{
+			name: "valid",
+			req: models.TranslationRequest{
+				Text:        []string{"Hello"},
+				SourceLang:  models.SourceLanguage.English,
+				TargetLang:  models.TargetLanguage.German,
+				Formality:   models.FormalityMore,
+				GlossaryId:  "def3a26b-3e84-45b3-84ae-0c0aaf3525f7",
+				TagHandling: models.TagXML,
+				IgnoreTags:  []string{"x"},
+			},
+		},
+		{
+			name:       "empty text and target",
+			req:        models.TranslationRequest{},
+			wantFields: []string{"Text", "TargetLang"},
+		},
+		{
+			name:       "glossary without source",
+			req:        models.TranslationRequest{Text: []string{"Hello"}, TargetLang: "DE", GlossaryId: "g"},
+			wantFields: []string{"GlossaryId"},
+		},
+		{
+			name:       "formality for unsupported target",
+			req:        models.TranslationRequest{Text: []string{"Hello"}, TargetLang: "EN-US", Formality: models.FormalityLess},
+			wantFields: []string{"Formality"},
+		},
+		{
+			name: "prefer formality for unsupported target",
+			req:  models.TranslationRequest{Text: []string{"Hello"}, TargetLang: "en-us", Formality: models.FormalityPreferLess},
+		},
+		{
+			name:       "tags without tag handling",
+			req:        models.TranslationRequest{Text: []string{"Hello"}, TargetLang: "DE", IgnoreTags: []string{"x"}, SplittingTags: []string{"p"}},
+			wantFields: []string{"IgnoreTags", "SplittingTags"},
+		},
+		{
+			name:       "invalid enum values",
+			req:        models.TranslationRequest{Text: []string{"Hello"}, TargetLang: "DE", SplitSentences: "2", ModelType: "fast", TagHandling: "markdown"},
+			wantFields: []string{"SplitSentences", "ModelType", "TagHandling"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkValidation(t, tt.req.Validate(), tt.wantFields)
+		})
+	}
+}
+
+func TestGlossaryRequests_Validate(t *testing.T) {
+	dict := models.Dictionary{SourceLanguage: "en", TargetLanguage: "de", Entries: "Hello\tHallo", EntriesFormat: "tsv"}
+
+	tests := []struct {
+		name       string
+		err        error
+		wantFields []string
+	}{
+		{name: "create valid", err: models.CreateGlossaryRequest{Name: "Terms", Dictionaries: []models.Dictionary{dict}}.Validate()},
+		{
+			name:       "create without name and dictionaries",
+			err:        models.CreateGlossaryRequest{}.Validate(),
+			wantFields: []string{"Name", "Dictionaries"},
+		},
+		{
+			name: "create with invalid dictionary",
+			err: models.CreateGlossaryRequest{Name: "Terms", Dictionaries: []models.Dictionary{
+				{SourceLanguage: "en", TargetLanguage: "EN", EntriesFormat: "xlsx"},
+			}}.Validate(),
+			wantFields: []string{"Dictionaries[0].TargetLanguage", "Dictionaries[0].Entries", "Dictionaries[0].EntriesFormat"},
+		},
+		{
+			name:       "edit with two dictionaries",
+			err:        models.EditGlossaryRequest{Dictionaries: []models.Dictionary{dict, dict}}.Validate(),
+			wantFields: []string{"Dictionaries"},
+		},
+		{name: "edit name only", err: models.EditGlossaryRequest{Name: "Renamed"}.Validate()},
+		{
+			name:       "language pair",
+			err:        models.GlossaryLangPair{SourceLanguage: "en"}.Validate(),
+			wantFields: []string{"TargetLanguage"},
+		},
+		{
+			name:       "rephrase with style and tone",
+			err:        models.RephraseRequest{Text: []string{"Hi"}, WritingStyle: models.StyleBusiness, Tone: models.ToneFriendly}.Validate(),
+			wantFields: []string{"Tone"},
+		},
+		{
+			name:       "file without file",
+			err:        models.FileTranslationRequest{TargetLang: "DE", GlossaryId: "g"}.Validate(),
+			wantFields: []string{"File", "GlossaryId"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkValidation(t, tt.err, tt.wantFields)
+		})
+	}
+}
+
+func TestClient_TranslateValidatesBeforeSending(t *testing.T) {
+	client := godeeplapi.NewClient("test-key", false,
+		godeeplapi.WithHTTPClient(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			t.Fatalf("unexpected request to %s", r.URL)
+			return nil, nil
+		})}))
+
+	_, err := client.Translate(context.Background(), models.TranslationRequest{
+		Text:       []string{"Hello"},
+		TargetLang: models.TargetLanguage.EnglishUS,
+		Formality:  models.FormalityMore,
+		IgnoreTags: []string{"x"},
+	})
+	checkValidation(t, err, []string{"Formality", "IgnoreTags"})
+}
+
+func checkValidation(t *testing.T, err error, wantFields []string) {
+	t.Helper()
+	if len(wantFields) == 0 {
+		if err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+		return
+	}
+
+	var verr *models.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Validate() error = %v, want *models.ValidationError", err)
+	}
+	if len(verr.Errors) != len(wantFields) {
+		t.Errorf("Validate() errors = %v, want fields %v", verr.Errors, wantFields)
+	}
+	for _, field := range wantFields {
+		if !verr.Has(field) {
+			t.Errorf("Validate() error %q does not mention %s", err, field)
+		}
+	}
+	if !strings.HasPrefix(err.Error(), "invalid request: ") {
+		t.Errorf("Error() = %q", err)
+	}
+}
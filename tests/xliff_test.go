@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AdolfZahid1/godeeplapi/formats/xliff"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+const xliff12Source = `<?xml version="1.0" encoding="UTF-8"?>
+<xliff version="1.2" xmlns="urn:oasis:names:tc:xliff:document:1.2">
+  <file source-language="en" datatype="plaintext" original="app">
+    <body>
+      <trans-unit id="greeting">
+        <source>Hello <x id="1"/> &amp; welcome</source>
+      </trans-unit>
+      <trans-unit id="done">
+        <source>Done</source>
+        <target state="final">Fertig</target>
+      </trans-unit>
+      <trans-unit id="brand" translate="no">
+        <source>DeepL</source>
+      </trans-unit>
+    </body>
+  </file>
+</xliff>
+`
+
+const xliff20Source = `<?xml version="1.0" encoding="UTF-8"?>
+<xliff version="2.0" xmlns="urn:oasis:names:tc:xliff:document:2.0" srcLang="en">
+  <file id="f1">
+    <unit id="u1">
+      <segment id="s1">
+        <source>Click <ph id="1"/> to continue</source>
+        <target/>
+      </segment>
+    </unit>
+  </file>
+</xliff>
+`
+
+func TestXLIFF_Translate(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      string
+		version     string
+		wantCount   int
+		wantIgnore  string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:       "XLIFF 1.2",
+			source:     xliff12Source,
+			version:    xliff.Version12,
+			wantCount:  1,
+			wantIgnore: "ph",
+			contains: []string{
+				`<target state="needs-review-translation">de:Hello <x id="1"/> &amp; welcome</target>`,
+				`<target state="final">Fertig</target>`,
+				`target-language="de"`,
+				`<trans-unit id="brand" translate="no">
+        <source>DeepL</source>
+      </trans-unit>`,
+			},
+		},
+		{
+			name:       "XLIFF 2.0",
+			source:     xliff20Source,
+			version:    xliff.Version20,
+			wantCount:  1,
+			wantIgnore: "cp",
+			contains: []string{
+				`<segment id="s1" state="translated">`,
+				`<target>de:Click <ph id="1"/> to continue</target>`,
+				`trgLang="de"`,
+			},
+			notContains: []string{"<target/>"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := xliff.Parse(strings.NewReader(tt.source))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if f.Version != tt.version {
+				t.Errorf("Version: got=%q, want=%q", f.Version, tt.version)
+			}
+
+			stub := &stubTranslator{}
+			n, err := xliff.Translate(context.Background(), stub, f, models.TranslationRequest{TargetLang: models.TargetLanguage.German})
+			if err != nil {
+				t.Fatalf("Translate() error = %v", err)
+			}
+			if n != tt.wantCount {
+				t.Errorf("translated: got=%d, want=%d", n, tt.wantCount)
+			}
+			req := stub.requests[0]
+			if req.TagHandling != models.TagXML || req.SourceLang != "EN" || !strings.Contains(strings.Join(req.IgnoreTags, ","), tt.wantIgnore) {
+				t.Errorf("unexpected request options: %+v", req)
+			}
+
+			var buf bytes.Buffer
+			if err := f.Write(&buf); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			out := buf.String()
+			for _, want := range tt.contains {
+				if !strings.Contains(out, want) {
+					t.Errorf("output is missing %q:\n%s", want, out)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(out, unwanted) {
+					t.Errorf("output should not contain %q:\n%s", unwanted, out)
+				}
+			}
+		})
+	}
+}
+
+func TestXLIFF_WriteUnchanged(t *testing.T) {
+	f, err := xliff.Parse(strings.NewReader(xliff12Source))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if buf.String() != xliff12Source {
+		t.Errorf("unchanged document was modified:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AdolfZahid1/godeeplapi/formats/yamllocale"
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+const yamlSource = `# Application strings
+en:
+  defaults: &defaults
+    save: Save
+  greeting: "Hello, %{name}" # shown on login
+  items: 3
+  enabled: true
+  form:
+    <<: *defaults
+    cancel: Cancel
+  list:
+    - First
+    - Second
+`
+
+func TestYAMLLocale_Translate(t *testing.T) {
+	source, err := yamllocale.Parse(strings.NewReader(yamlSource))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := source.Root(); got != "en" {
+		t.Fatalf("Root() got=%q, want=%q", got, "en")
+	}
+	existing, err := yamllocale.Parse(strings.NewReader("pt-BR:\n  form:\n    cancel: Cancelar\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	stub := &stubTranslator{}
+	got, err := yamllocale.Translate(context.Background(), stub, source,
+		models.TranslationRequest{TargetLang: models.TargetLanguage.PortugueseBR},
+		yamllocale.WithExisting(existing))
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+
+	if root := got.Root(); root != "pt-BR" {
+		t.Errorf("Root() got=%q, want=%q", root, "pt-BR")
+	}
+
+	tests := []struct {
+		name string
+		path []string
+		want string
+	}{
+		{name: "anchored value", path: []string{"defaults", "save"}, want: "pt-br:Save"},
+		{name: "placeholder restored", path: []string{"greeting"}, want: "pt-br:Hello, %{name}"},
+		{name: "existing value reused", path: []string{"form", "cancel"}, want: "Cancelar"},
+		{name: "sequence item", path: []string{"list", "1"}, want: "pt-br:Second"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, ok := got.Lookup(tt.path...)
+			if !ok {
+				t.Fatalf("missing value %v", tt.path)
+			}
+			if v != tt.want {
+				t.Errorf("got=%q, want=%q", v, tt.want)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := got.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"# Application strings", "# shown on login", "&defaults", "<<: *defaults", "items: 3", "enabled: true"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(source.Root(), "pt") {
+		t.Errorf("source document was modified")
+	}
+}
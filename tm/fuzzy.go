@@ -0,0 +1,121 @@
+package tm
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+// numberToken stands in for every number during fuzzy matching, so segments
+// that only differ in their numbers are nearly identical.
+const numberToken = '\uE000'
+
+// numberPenalty is subtracted from the score for every number that differs.
+const numberPenalty = 0.01
+
+var numbers = regexp.MustCompile(`\d+(?:[.,]\d+)*`)
+
+// Search returns the entries whose source is at least threshold similar to
+// text, best first and at most limit of them (all when limit <= 0). An empty
+// sourceLang matches entries of any source language.
+//
+// Similarity is one minus the edit distance divided by the length of the
+// longer segment, computed after collapsing whitespace and replacing numbers
+// with a common token. Every differing number then costs a small penalty.
+func (m *Memory) Search(sourceLang, targetLang, text string, threshold float64, limit int) []models.MemoryMatch {
+	sourceLang = normalizeSourceLang(sourceLang)
+	targetLang = normalizeLang(targetLang)
+	query := newFuzzyKey(text)
+
+	m.mu.RLock()
+	var matches []models.MemoryMatch
+	for k, e := range m.entries {
+		if k.targetLang != targetLang || (sourceLang != "" && k.sourceLang != sourceLang) {
+			continue
+		}
+		candidate := newFuzzyKey(k.source)
+		if !query.canReach(candidate, threshold) {
+			continue
+		}
+		if score := query.score(candidate); score >= threshold {
+			matches = append(matches, models.MemoryMatch{Source: e.Source, Target: e.Target, Score: score})
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Source < matches[j].Source
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// fuzzyKey is a segment prepared for fuzzy comparison.
+type fuzzyKey struct {
+	runes   []rune
+	numbers []string
+}
+
+func newFuzzyKey(text string) fuzzyKey {
+	normalized := Normalize(text)
+	return fuzzyKey{
+		runes:   []rune(numbers.ReplaceAllString(normalized, string(numberToken))),
+		numbers: numbers.FindAllString(normalized, -1),
+	}
+}
+
+// canReach reports whether the length difference alone leaves the threshold reachable.
+func (a fuzzyKey) canReach(b fuzzyKey, threshold float64) bool {
+	longer := max(len(a.runes), len(b.runes))
+	if longer == 0 {
+		return true
+	}
+	diff := len(a.runes) - len(b.runes)
+	if diff < 0 {
+		diff = -diff
+	}
+	return 1-float64(diff)/float64(longer) >= threshold
+}
+
+func (a fuzzyKey) score(b fuzzyKey) float64 {
+	longer := max(len(a.runes), len(b.runes))
+	if longer == 0 {
+		return 1
+	}
+	score := 1 - float64(levenshtein(a.runes, b.runes))/float64(longer)
+
+	for i := 0; i < max(len(a.numbers), len(b.numbers)); i++ {
+		if i >= len(a.numbers) || i >= len(b.numbers) || a.numbers[i] != b.numbers[i] {
+			score -= numberPenalty
+		}
+	}
+	return max(score, 0)
+}
+
+// levenshtein returns the edit distance between two rune slices.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
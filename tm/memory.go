@@ -0,0 +1,210 @@
+// Package tm implements a translation memory: a store of approved
+// translations that is consulted before text is sent to DeepL.
+package tm
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is a translated segment.
+type Entry struct {
+	SourceLang string    `json:"source_lang"`
+	TargetLang string    `json:"target_lang"`
+	Source     string    `json:"source"`
+	Target     string    `json:"target"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+}
+
+type key struct {
+	sourceLang string
+	targetLang string
+	source     string
+}
+
+// textKey identifies the entries of a text in all source languages.
+type textKey struct {
+	targetLang string
+	source     string
+}
+
+// Memory is a translation memory keyed by language pair and normalized source
+// segment. It is safe for concurrent use.
+type Memory struct {
+	mu      sync.RWMutex
+	entries map[key]Entry
+	// sources lists the source languages stored for a target language and
+	// text, for lookups without a source language.
+	sources map[textKey][]string
+	file    *os.File
+}
+
+// New returns an empty in-memory translation memory.
+func New() *Memory {
+	return &Memory{entries: make(map[key]Entry), sources: make(map[textKey][]string)}
+}
+
+// Open loads a file-backed translation memory. Entries are stored as JSON
+// lines and every Add is appended to the file. The file is created if it does
+// not exist.
+func Open(path string) (*Memory, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("tm: error opening memory file: %w", err)
+	}
+
+	m := New()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("tm: line %d: %w", line, err)
+		}
+		m.put(e)
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("tm: error reading memory file: %w", err)
+	}
+
+	m.file = file
+	return m, nil
+}
+
+// Close closes the backing file of a file-backed memory.
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.file == nil {
+		return nil
+	}
+	err := m.file.Close()
+	m.file = nil
+	return err
+}
+
+// Add stores an entry, replacing any entry with the same language pair and
+// normalized source.
+func (m *Memory) Add(e Entry) error {
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now().UTC()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.put(e)
+
+	if m.file != nil {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("tm: error encoding entry: %w", err)
+		}
+		if _, err := m.file.Write(append(data, '\n')); err != nil {
+			return fmt.Errorf("tm: error writing entry: %w", err)
+		}
+	}
+	return nil
+}
+
+func (m *Memory) put(e Entry) {
+	e.SourceLang = normalizeSourceLang(e.SourceLang)
+	e.TargetLang = normalizeLang(e.TargetLang)
+	k := key{e.SourceLang, e.TargetLang, Normalize(e.Source)}
+	if _, ok := m.entries[k]; !ok {
+		tk := textKey{k.targetLang, k.source}
+		m.sources[tk] = append(m.sources[tk], k.sourceLang)
+	}
+	m.entries[k] = e
+}
+
+// Get returns the entry for an exact (normalized) match. An empty sourceLang
+// matches entries of any source language; the newest of them is returned,
+// and the first source language in alphabetical order among equally new
+// ones.
+func (m *Memory) Get(sourceLang, targetLang, text string) (Entry, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sourceLang = normalizeSourceLang(sourceLang)
+	targetLang = normalizeLang(targetLang)
+	text = Normalize(text)
+
+	if sourceLang != "" {
+		e, ok := m.entries[key{sourceLang, targetLang, text}]
+		return e, ok
+	}
+	var best Entry
+	found := false
+	for _, lang := range m.sources[textKey{targetLang, text}] {
+		e := m.entries[key{lang, targetLang, text}]
+		if !found || e.CreatedAt.After(best.CreatedAt) || (e.CreatedAt.Equal(best.CreatedAt) && e.SourceLang < best.SourceLang) {
+			best, found = e, true
+		}
+	}
+	return best, found
+}
+
+// Lookup returns the stored translation of text. It satisfies
+// godeeplapi.TranslationMemory.
+func (m *Memory) Lookup(sourceLang, targetLang, text string) (string, bool) {
+	e, ok := m.Get(sourceLang, targetLang, text)
+	return e.Target, ok
+}
+
+// Len returns the number of entries.
+func (m *Memory) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.entries)
+}
+
+// Entries returns all entries ordered by language pair and source.
+func (m *Memory) Entries() []Entry {
+	m.mu.RLock()
+	entries := make([]Entry, 0, len(m.entries))
+	for _, e := range m.entries {
+		entries = append(entries, e)
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.SourceLang != b.SourceLang {
+			return a.SourceLang < b.SourceLang
+		}
+		if a.TargetLang != b.TargetLang {
+			return a.TargetLang < b.TargetLang
+		}
+		return a.Source < b.Source
+	})
+	return entries
+}
+
+// Normalize prepares a source segment for matching: surrounding whitespace is
+// removed and inner runs of whitespace are collapsed to a single space.
+func Normalize(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func normalizeLang(lang string) string {
+	return strings.ToUpper(strings.ReplaceAll(lang, "_", "-"))
+}
+
+// normalizeSourceLang drops the variant, since DeepL source languages have none.
+func normalizeSourceLang(lang string) string {
+	base, _, _ := strings.Cut(normalizeLang(lang), "-")
+	return base
+}
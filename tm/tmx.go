@@ -0,0 +1,195 @@
+package tm
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+type tmxDocument struct {
+	XMLName xml.Name  `xml:"tmx"`
+	Version string    `xml:"version,attr"`
+	Header  tmxHeader `xml:"header"`
+	Units   []tmxUnit `xml:"body>tu"`
+}
+
+type tmxHeader struct {
+	CreationTool        string `xml:"creationtool,attr"`
+	CreationToolVersion string `xml:"creationtoolversion,attr"`
+	SegType             string `xml:"segtype,attr"`
+	OTMF                string `xml:"o-tmf,attr"`
+	AdminLang           string `xml:"adminlang,attr"`
+	SrcLang             string `xml:"srclang,attr"`
+	DataType            string `xml:"datatype,attr"`
+}
+
+type tmxUnit struct {
+	SrcLang  string       `xml:"srclang,attr,omitempty"`
+	Variants []tmxVariant `xml:"tuv"`
+}
+
+type tmxVariant struct {
+	Lang string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	// Older TMX versions use a plain lang attribute.
+	LegacyLang string `xml:"lang,attr,omitempty"`
+	Seg        tmxSeg `xml:"seg"`
+}
+
+type tmxSeg struct {
+	Inner string `xml:",innerxml"`
+}
+
+// ImportTMX reads a TMX 1.4 document and adds an entry for every pair of
+// source and target variants. It returns the number of entries added.
+func (m *Memory) ImportTMX(r io.Reader) (int, error) {
+	var doc tmxDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return 0, fmt.Errorf("tm: error decoding TMX: %w", err)
+	}
+
+	added := 0
+	for i, unit := range doc.Units {
+		srcLang := unit.SrcLang
+		if srcLang == "" {
+			srcLang = doc.Header.SrcLang
+		}
+
+		texts := make(map[string]string)
+		var langs []string
+		for _, v := range unit.Variants {
+			lang := v.Lang
+			if lang == "" {
+				lang = v.LegacyLang
+			}
+			text, err := segText(v.Seg.Inner)
+			if err != nil {
+				return added, fmt.Errorf("tm: unit %d: %w", i+1, err)
+			}
+			if _, ok := texts[lang]; !ok {
+				langs = append(langs, lang)
+			}
+			texts[lang] = text
+		}
+
+		for _, source := range langs {
+			if srcLang != "*all*" && !strings.EqualFold(source, srcLang) {
+				continue
+			}
+			for _, target := range langs {
+				if target == source || texts[source] == "" || texts[target] == "" {
+					continue
+				}
+				if err := m.Add(Entry{SourceLang: source, TargetLang: target, Source: texts[source], Target: texts[target]}); err != nil {
+					return added, err
+				}
+				added++
+			}
+		}
+	}
+
+	return added, nil
+}
+
+// segText extracts the text of a <seg>, dropping inline markup and the
+// native codes inside bpt, ept, ph and it elements.
+func segText(inner string) (string, error) {
+	dec := xml.NewDecoder(strings.NewReader("<seg>" + inner + "</seg>"))
+	var b strings.Builder
+	skip := 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return b.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "bpt", "ept", "ph", "it":
+				skip++
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "bpt", "ept", "ph", "it":
+				skip--
+			}
+		case xml.CharData:
+			if skip == 0 {
+				b.Write(t)
+			}
+		}
+	}
+}
+
+// ExportTMX writes all entries as a TMX 1.4 document. Entries with the same
+// source language and text are grouped into one translation unit.
+func (m *Memory) ExportTMX(w io.Writer) error {
+	entries := m.Entries()
+
+	srcLang := "*all*"
+	if len(entries) > 0 {
+		srcLang = tmxLang(entries[0].SourceLang)
+		for _, e := range entries {
+			if tmxLang(e.SourceLang) != srcLang {
+				srcLang = "*all*"
+				break
+			}
+		}
+	}
+
+	doc := tmxDocument{
+		Version: "1.4",
+		Header: tmxHeader{
+			CreationTool:        "godeeplapi",
+			CreationToolVersion: "1",
+			SegType:             "sentence",
+			OTMF:                "godeeplapi",
+			AdminLang:           "en",
+			SrcLang:             srcLang,
+			DataType:            "plaintext",
+		},
+	}
+
+	index := make(map[[2]string]int)
+	for _, e := range entries {
+		k := [2]string{e.SourceLang, Normalize(e.Source)}
+		i, ok := index[k]
+		if !ok {
+			i = len(doc.Units)
+			index[k] = i
+			doc.Units = append(doc.Units, tmxUnit{
+				SrcLang:  tmxLang(e.SourceLang),
+				Variants: []tmxVariant{{Lang: tmxLang(e.SourceLang), Seg: tmxSeg{Inner: escape(e.Source)}}},
+			})
+		}
+		doc.Units[i].Variants = append(doc.Units[i].Variants, tmxVariant{Lang: tmxLang(e.TargetLang), Seg: tmxSeg{Inner: escape(e.Target)}})
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(xml.Header)
+	enc := xml.NewEncoder(bw)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("tm: error encoding TMX: %w", err)
+	}
+	bw.WriteString("\n")
+	return bw.Flush()
+}
+
+func escape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// tmxLang converts a DeepL code such as EN-US to the RFC 4646 form en-US.
+func tmxLang(lang string) string {
+	return models.Language(lang).Locale("-")
+}
@@ -1,217 +1,196 @@
 package godeeplapi
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"slices"
+
 	"github.com/AdolfZahid1/godeeplapi/models"
-	"mime"
-	"net/http"
-	"os"
-	"path/filepath"
-	"time"
 )
 
 // Translate text using the DeepL API
 func (c *Client) Translate(ctx context.Context, request models.TranslationRequest) ([]string, error) {
-	if c.authKey == "" {
-		return nil, fmt.Errorf("DeepL API token is empty")
-	}
-
-	respBody, err := c.doRequest(ctx, "POST", "/translate", request, nil)
+	results, err := c.TranslateDetailed(ctx, request)
 	if err != nil {
 		return nil, err
 	}
 
-	var response models.TranslationResponse
-	if err := json.Unmarshal(respBody, &response); err != nil {
-		return nil, fmt.Errorf("error unmarshaling response: %w", err)
+	translations := make([]string, len(results))
+	for i, result := range results {
+		translations[i] = result.Text
 	}
+	return translations, nil
+}
 
-	if len(response.Translations) == 0 {
-		return nil, fmt.Errorf("no translations in response")
+// TranslateDetailed translates text like Translate, but returns the detected
+// source language of every text and whether it was served from the
+// translation memory.
+func (c *Client) TranslateDetailed(ctx context.Context, request models.TranslationRequest) ([]models.Translation, error) {
+	if c.authKey == "" && c.offline == nil {
+		return nil, fmt.Errorf("DeepL API token is empty")
 	}
-
-	var translations []string
-	for _, translation := range response.Translations {
-		translations = append(translations, translation.Text)
+	request.SourceLang = request.SourceLang.SourceCode()
+	request.TargetLang = request.TargetLang.TargetCode()
+	if err := request.ValidateWith(c.languageSupport(ctx)); err != nil {
+		return nil, err
 	}
 
-	c.logger.Info("Translated %d text(s)", len(translations))
-	return translations, nil
-}
-
-// TranslateFile uploads a file for translation and monitors progress
-func (c *Client) TranslateFile(ctx context.Context, req models.FileTranslationRequest, targetDir string) (string, error) {
-	if c.authKey == "" {
-		return "", fmt.Errorf("DeepL API token is empty")
+	results := make([]models.Translation, len(request.Text))
+	var misses []int
+	for i, text := range request.Text {
+		if c.lookupMemory(request, text, &results[i]) {
+			continue
+		}
+		misses = append(misses, i)
 	}
 
-	// Validate inputs
-	if req.File == nil {
-		return "", fmt.Errorf("file is required")
+	if len(misses) == 0 {
+		c.logger.Info("Translated %d text(s) from translation memory", len(results))
+		return results, nil
 	}
-	if req.TargetLang == "" {
-		return "", fmt.Errorf("target language is required")
+
+	apiRequest := request
+	if len(misses) < len(request.Text) {
+		apiRequest.Text = make([]string, len(misses))
+		for i, index := range misses {
+			apiRequest.Text[i] = request.Text[index]
+		}
 	}
 
-	// Upload file
-	respBody, err := c.uploadFile(ctx, "/document", req)
+	var translations []models.Translation
+	var err error
+	if c.offline != nil {
+		translations, err = c.translateOffline(ctx, apiRequest)
+	} else {
+		translations, err = c.translateAPI(ctx, apiRequest)
+	}
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	if len(translations) != len(misses) {
+		return nil, fmt.Errorf("expected %d translations, got %d", len(misses), len(translations))
 	}
 
-	var response models.DocumentResponse
-	if err := json.Unmarshal(respBody, &response); err != nil {
-		return "", fmt.Errorf("error unmarshaling response: %w", err)
-	}
-
-	// Create a child context with timeout for the monitoring process
-	monitorCtx, cancel := context.WithTimeout(ctx, 60*time.Minute)
-	defer cancel()
-
-	// Create result channel to get the final file path or error
-	resultCh := make(chan struct {
-		path string
-		err  error
-	}, 1)
-
-	// Start monitoring in a goroutine
-	go func() {
-		path, err := c.monitorAndDownload(monitorCtx, response.DocumentId, response.DocumentKey, targetDir)
-		resultCh <- struct {
-			path string
-			err  error
-		}{path, err}
-	}()
-
-	// Wait for result or context cancellation
-	select {
-	case result := <-resultCh:
-		return result.path, result.err
-	case <-ctx.Done():
-		return "", ctx.Err()
+	for i, index := range misses {
+		matches := results[index].MemoryMatches
+		results[index] = translations[i]
+		results[index].MemoryMatches = matches
 	}
+
+	c.logger.Info("Translated %d text(s), %d from translation memory", len(results), len(results)-len(misses))
+	return results, nil
 }
 
-// monitorAndDownload checks document status and downloads when ready
-func (c *Client) monitorAndDownload(ctx context.Context, documentId, documentKey, targetDir string) (string, error) {
-	for {
-		select {
-		case <-ctx.Done():
-			return "", ctx.Err()
-		default:
-			status, err := c.checkDocumentStatus(ctx, documentId, documentKey)
-			if err != nil {
-				c.logger.Error("Error checking document status: %v", err)
-				return "", err
-			}
+// translateAPI sends the request to the /translate endpoint.
+func (c *Client) translateAPI(ctx context.Context, request models.TranslationRequest) ([]models.Translation, error) {
+	chars := EstimateCharacters(request)
+	if c.budget != nil {
+		if err := c.budget.Reserve(ctx, chars); err != nil {
+			return nil, err
+		}
+	}
 
-			switch status.DocumentStatus {
-			case "done":
-				c.logger.Info("Document translation completed, downloading...")
-				return c.downloadDocument(ctx, documentId, documentKey, targetDir)
-
-			case "translating":
-				waitTime := time.Duration(status.SecondsRemaining+1) * time.Second
-				c.logger.Debug("Document is translating, seconds remaining: %d. Checking again in %v",
-					status.SecondsRemaining, waitTime)
-
-				select {
-				case <-time.After(waitTime):
-					// Continue checking
-				case <-ctx.Done():
-					return "", ctx.Err()
-				}
-
-			case "queued":
-				c.logger.Debug("Document is queued for translation. Checking again in 10 seconds")
-				select {
-				case <-time.After(10 * time.Second):
-					// Continue checking
-				case <-ctx.Done():
-					return "", ctx.Err()
-				}
-
-			case "error":
-				errMsg := fmt.Sprintf("Document processing error: %s", status.ErrorMessage)
-				c.logger.Error(errMsg)
-				return "", fmt.Errorf(errMsg)
-
-			default:
-				errMsg := fmt.Sprintf("Unknown document status: %s", status.DocumentStatus)
-				c.logger.Error(errMsg)
-				return "", fmt.Errorf(errMsg)
-			}
+	respBody, err := c.doRequest(ctx, "POST", "/translate", request, nil)
+	if err != nil {
+		// Characters of failed requests are not billed
+		if c.budget != nil {
+			c.budget.Release(ctx, chars)
 		}
+		return nil, err
 	}
-}
 
-// checkDocumentStatus checks the status of a document translation
-func (c *Client) checkDocumentStatus(ctx context.Context, documentId, documentKey string) (*models.DocumentStatusResponse, error) {
-	requestBody := map[string]string{"document_key": documentKey}
+	var response models.TranslationResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("error unmarshaling response: %w", err)
+	}
+
+	if len(response.Translations) == 0 {
+		return nil, fmt.Errorf("no translations in response")
+	}
+	return response.Translations, nil
+}
 
-	respBody, err := c.doRequest(ctx, "POST", "/document/"+documentId, requestBody, nil)
+// translateOffline translates the request with the translator set up by
+// WithOfflineTranslator.
+func (c *Client) translateOffline(ctx context.Context, request models.TranslationRequest) ([]models.Translation, error) {
+	texts, err := c.offline.Translate(ctx, request)
 	if err != nil {
 		return nil, err
 	}
 
-	var status models.DocumentStatusResponse
-	if err := json.Unmarshal(respBody, &status); err != nil {
-		return nil, fmt.Errorf("error unmarshaling status response: %w", err)
+	translations := make([]models.Translation, len(texts))
+	for i, text := range texts {
+		translations[i] = models.Translation{DetectedSourceLanguage: request.SourceLang.String(), Text: text}
 	}
-
-	return &status, nil
+	return translations, nil
 }
 
-// downloadDocument downloads a translated document
-func (c *Client) downloadDocument(ctx context.Context, documentId, documentKey, targetDir string) (string, error) {
-	endpoint := "/document/" + documentId + "/result"
-	requestBody := map[string]string{"document_key": documentKey}
+// lookupMemory fills result from the translation memory. It reports whether
+// the text is fully served, so that it does not need to be sent to the API.
+func (c *Client) lookupMemory(request models.TranslationRequest, text string, result *models.Translation) bool {
+	if c.memory == nil {
+		return false
+	}
 
-	// Create temp HTTP request to get the filename
-	jsonData, _ := json.Marshal(requestBody)
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+endpoint, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("error creating request: %w", err)
+	if translated, ok := c.memory.Lookup(request.SourceLang.String(), request.TargetLang.String(), text); ok {
+		*result = models.Translation{DetectedSourceLanguage: request.SourceLang.String(), Text: translated, FromMemory: true, MemoryScore: 1}
+		return true
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "DeepL-Auth-Key "+c.authKey)
 
-	// Send HEAD request first to get the filename
-	req.Method = "HEAD"
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("error getting file info: %w", err)
+	fuzzy, ok := c.memory.(FuzzyTranslationMemory)
+	if c.fuzzy == nil || !ok {
+		return false
+	}
+
+	limit := c.fuzzy.MaxMatches
+	if limit <= 0 {
+		limit = 3
+	}
+	matches := fuzzy.Search(request.SourceLang.String(), request.TargetLang.String(), text, c.fuzzy.Threshold, limit)
+	if len(matches) == 0 {
+		return false
 	}
-	resp.Body.Close()
 
-	// Get filename from Content-Disposition header
-	outputFilename := "translated_document"
-	if contentDisposition := resp.Header.Get("Content-Disposition"); contentDisposition != "" {
-		if _, params, err := mime.ParseMediaType(contentDisposition); err == nil {
-			if filename, ok := params["filename"]; ok {
-				outputFilename = filename
+	if c.fuzzy.UseBestMatch {
+		for _, match := range matches {
+			if !sameNumbers(text, match.Source) {
+				continue
 			}
+			*result = models.Translation{
+				DetectedSourceLanguage: request.SourceLang.String(),
+				Text:                   match.Target,
+				FromMemory:             true,
+				MemoryScore:            match.Score,
+				MemoryMatches:          matches,
+			}
+			return true
 		}
 	}
 
-	// Create target directory if needed
-	if targetDir == "" {
-		targetDir = "."
-	}
+	result.MemoryMatches = matches
+	return false
+}
 
-	if err := os.MkdirAll(targetDir, 0755); err != nil {
-		return "", fmt.Errorf("error creating target directory: %w", err)
-	}
+var memoryNumbers = regexp.MustCompile(`\d+(?:[.,]\d+)*`)
 
-	outputPath := filepath.Join(targetDir, outputFilename)
+// sameNumbers reports whether a and b contain the same numbers in the same
+// order.
+func sameNumbers(a, b string) bool {
+	return slices.Equal(memoryNumbers.FindAllString(a, -1), memoryNumbers.FindAllString(b, -1))
+}
 
-	// Now download the actual file
-	if err := c.downloadToFile(ctx, endpoint, requestBody, outputPath); err != nil {
+// TranslateFile uploads a file for translation, waits for it and downloads
+// the result to targetDir. Set req.OnProgress to follow it. The wait is
+// limited by ctx and the PollStrategy of the client. Use
+// UploadDocument and ResumeDocument instead to keep the document handle, so
+// that the job survives a restart.
+func (c *Client) TranslateFile(ctx context.Context, req models.FileTranslationRequest, targetDir string) (string, error) {
+	handle, err := c.UploadDocument(ctx, req)
+	if err != nil {
 		return "", err
 	}
 
-	return outputPath, nil
+	return c.ResumeDocument(ctx, *handle, targetDir)
 }
@@ -0,0 +1,92 @@
+package godeeplapi
+
+import (
+	"context"
+	"io"
+
+	"github.com/AdolfZahid1/godeeplapi/models"
+)
+
+// Translator is the text translation part of the client. The file format
+// packages accept it instead of *Client so they can be driven by other
+// implementations as well.
+type Translator interface {
+	Translate(ctx context.Context, request models.TranslationRequest) ([]string, error)
+}
+
+// Improver is the text improvement part of the client.
+type Improver interface {
+	ImproveText(ctx context.Context, req models.RephraseRequest) (string, error)
+}
+
+// GlossaryManager is the glossary part of the client.
+type GlossaryManager interface {
+	ListLangPairsSupportedByGlossaries(ctx context.Context) (*models.GlossaryListResponse, error)
+	CreateGlossary(ctx context.Context, req models.CreateGlossaryRequest) (*models.CreateGlossaryResponse, error)
+	ListAllGlossaries(ctx context.Context) (*models.AllGlossaryListResponse, error)
+	GetGlossaryByID(ctx context.Context, id string) (*models.Glossary, error)
+	EditGlossary(ctx context.Context, id string, req models.EditGlossaryRequest) (*models.Glossary, error)
+	DeleteGlossary(ctx context.Context, id string) error
+	DeleteAllLangDictionaries(ctx context.Context, id string, query models.GlossaryLangPair) error
+	GetGlossaryEntries(ctx context.Context, id string, query models.GlossaryLangPair) (*models.GlossaryEntriesResponse, error)
+	ReplaceOrCreateDictionaryInGlossary(ctx context.Context, id string, req models.Dictionary) (*models.EditOrCreateDictionaryInGlossaryResponse, error)
+}
+
+// DocumentTranslator is the document translation part of the client,
+// including the steps of the document lifecycle.
+type DocumentTranslator interface {
+	TranslateFile(ctx context.Context, req models.FileTranslationRequest, targetDir string) (string, error)
+	TranslateDocument(ctx context.Context, file io.Reader, req models.FileTranslationRequest) (io.ReadCloser, *models.DocumentResult, error)
+	UploadDocument(ctx context.Context, req models.FileTranslationRequest) (*models.DocumentHandle, error)
+	GetDocumentStatus(ctx context.Context, handle models.DocumentHandle) (*models.DocumentStatusResponse, error)
+	WaitForDocument(ctx context.Context, handle models.DocumentHandle) (*models.DocumentStatusResponse, error)
+	ResumeDocument(ctx context.Context, handle models.DocumentHandle, targetDir string) (string, error)
+	OpenDocument(ctx context.Context, handle models.DocumentHandle) (io.ReadCloser, *models.DocumentResult, error)
+	DownloadDocument(ctx context.Context, handle models.DocumentHandle, targetDir string) (string, error)
+	DownloadDocumentTo(ctx context.Context, handle models.DocumentHandle, w io.Writer) (*models.DocumentResult, error)
+	DownloadDocumentBytes(ctx context.Context, handle models.DocumentHandle) ([]byte, *models.DocumentResult, error)
+}
+
+// Service combines all parts of the client. *Client, *Fake and *Recorder
+// implement it, so code that depends on Service can be tested offline.
+type Service interface {
+	Translator
+	Improver
+	GlossaryManager
+	DocumentTranslator
+}
+
+var (
+	_ Translator         = (*Client)(nil)
+	_ Improver           = (*Client)(nil)
+	_ GlossaryManager    = (*Client)(nil)
+	_ DocumentTranslator = (*Client)(nil)
+	_ Service            = (*Client)(nil)
+)
+
+// TranslationMemory provides approved translations that are used instead of
+// calling the API. See the tm package for an implementation.
+type TranslationMemory interface {
+	Lookup(sourceLang, targetLang, text string) (string, bool)
+}
+
+// FuzzyTranslationMemory is a TranslationMemory that can also find similar
+// segments. *tm.Memory implements it.
+type FuzzyTranslationMemory interface {
+	TranslationMemory
+	Search(sourceLang, targetLang, text string, threshold float64, limit int) []models.MemoryMatch
+}
+
+// FuzzyOptions configures fuzzy translation memory lookups.
+type FuzzyOptions struct {
+	// Threshold is the minimum similarity between 0 and 1 for a match.
+	Threshold float64
+	// MaxMatches limits the matches returned per text. Zero means 3.
+	MaxMatches int
+	// UseBestMatch serves the best match instead of calling the API. Matches
+	// whose numbers differ from the text are never served, since their
+	// translation would carry the wrong numbers; the API is called and they
+	// are only returned alongside. When false, matches are always returned
+	// alongside the API translation.
+	UseBestMatch bool
+}